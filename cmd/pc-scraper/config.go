@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func runConfig(ctx context.Context, app *app, args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		fs := newFlagSet("config", "validate",
+			"Carrega a configuração (arquivo de -config/CONFIG_FILE mais variáveis de ambiente)\n"+
+				"e lista todos os problemas encontrados.")
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "-help") {
+			fs.Usage()
+			return nil
+		}
+		fs.Usage()
+		return usagef("use \"config validate\"")
+	}
+
+	fs := newFlagSet("config validate", "", "Valida a configuração e sai com código 1 se ela for inválida.")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return fmt.Errorf("configuração inválida:\n%w", err)
+	}
+
+	source := app.configFile
+	if source == "" {
+		source = "padrão"
+	}
+	fmt.Fprintf(app.stdout, "configuração válida (%s): %d categorias, %d lojas\n",
+		source, len(cfg.Categories), len(cfg.Stores))
+	return nil
+}
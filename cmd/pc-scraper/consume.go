@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
+	"github.com/vitor-labes/pc-scraper/internal/queue"
+	"github.com/vitor-labes/pc-scraper/internal/repository"
+)
+
+func runConsume(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("consume", "[flags]",
+		"Consome a fila de produtos e grava cada mensagem no PostgreSQL até receber SIGINT/SIGTERM.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+
+	serveMetrics(cfg.Metrics.ConsumerPort)
+
+	slog.Info("iniciando consumer",
+		"config", app.configFile,
+		"queue", cfg.Queue.Name,
+	)
+
+	// Conection
+	repo, err := repository.NewProductRepository(cfg.Database.DSN())
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	// Save metrics DB
+	handler := func(ctx context.Context, product domain.Product) error {
+		startTime := time.Now()
+
+		err := repo.Save(ctx, product)
+
+		duration := time.Since(startTime).Seconds()
+		metrics.MessageProcessingDuration.Observe(duration)
+
+		if err != nil {
+			metrics.MessagesProcessed.WithLabelValues("error").Inc()
+			metrics.DatabaseInserts.WithLabelValues("error").Inc()
+			return err
+		}
+
+		metrics.MessagesProcessed.WithLabelValues("success").Inc()
+		metrics.DatabaseInserts.WithLabelValues("success").Inc()
+		return nil
+	}
+
+	// Create consumer
+	consumer, err := queue.NewConsumer(cfg.Queue, handler)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	if err := consumer.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	slog.Info("consumer encerrado com sucesso")
+	return nil
+}
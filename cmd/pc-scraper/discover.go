@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/discovery"
+)
+
+func runDiscover(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("discover", "[flags]",
+		"Lista as categorias de hardware da loja a partir do menu e do sitemap e, com -write,\n"+
+			"acrescenta as novas ao arquivo de categorias.")
+	baseURL := fs.String("base", "https://www.pichau.com.br", "URL base da loja")
+	prefix := fs.String("prefix", "/hardware/", "prefixo de caminho das categorias")
+	sitemapURL := fs.String("sitemap", "", "URL do sitemap (padrão: <base>/sitemap.xml)")
+	useMenu := fs.Bool("menu", true, "ler o menu de navegação com o navegador")
+	counts := fs.Bool("counts", true, "visitar cada categoria para contar produtos (-counts=false pula a contagem)")
+	headless := fs.Bool("headless", true, "executar o navegador em modo headless")
+	write := fs.String("write", "", "arquivo de categorias a atualizar")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *sitemapURL == "" {
+		*sitemapURL = strings.TrimSuffix(*baseURL, "/") + "/sitemap.xml"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	var fromSitemap []discovery.Category
+	client := &http.Client{Timeout: 30 * time.Second}
+	urls, err := discovery.FetchSitemap(ctx, client, *sitemapURL)
+	if err != nil {
+		slog.Warn("erro ao ler sitemap", "url", *sitemapURL, "error", err)
+	}
+	fromSitemap = discovery.CategoriesFromURLs(urls, *prefix, "sitemap")
+	slog.Info("sitemap processado", "urls", len(urls), "categories", len(fromSitemap))
+
+	var page playwright.Page
+	if *useMenu || *counts {
+		pw, err := playwright.Run()
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar playwright: %w", err)
+		}
+		defer pw.Stop()
+
+		browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
+			Headless: playwright.Bool(*headless),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao abrir navegador: %w", err)
+		}
+		defer browser.Close()
+
+		page, err = browser.NewPage(playwright.BrowserNewPageOptions{
+			UserAgent: playwright.String(config.NewDefault().UserAgent),
+			Locale:    playwright.String("pt-BR"),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao criar página: %w", err)
+		}
+	}
+
+	var fromMenu []discovery.Category
+	if *useMenu {
+		if err := navigate(page, *baseURL); err != nil {
+			slog.Warn("erro ao abrir página inicial", "error", err)
+		} else if fromMenu, err = discovery.MenuCategories(page, *prefix); err != nil {
+			slog.Warn("erro ao ler menu", "error", err)
+		}
+		slog.Info("menu processado", "categories", len(fromMenu))
+	}
+
+	categories := discovery.Merge(fromMenu, fromSitemap)
+	if len(categories) == 0 {
+		return errors.New("nenhuma categoria encontrada")
+	}
+
+	if *counts {
+		for i, c := range categories {
+			if ctx.Err() != nil {
+				break
+			}
+			categories[i].Products = countProducts(page, c.URL)
+		}
+	}
+
+	w := tabwriter.NewWriter(app.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NOME\tPRODUTOS\tORIGEM\tURL")
+	for _, c := range categories {
+		products := "-"
+		if c.Products > 0 {
+			products = fmt.Sprint(c.Products)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Name, products, c.Source, c.URL)
+	}
+	w.Flush()
+
+	if *write == "" {
+		return nil
+	}
+
+	total, added, err := updateCategoriesFile(app, *write, categories)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("arquivo de categorias atualizado",
+		"path", *write,
+		"added", added,
+		"total", total,
+	)
+	return nil
+}
+
+// updateCategoriesFile appends the discovered categories to the file at
+// path. A missing file starts from the active configuration's categories,
+// so the file written keeps their filters and kinds.
+func updateCategoriesFile(app *app, path string, discovered []discovery.Category) (total, added int, err error) {
+	existing, err := config.LoadCategories(path)
+	if errors.Is(err, os.ErrNotExist) {
+		cfg, cfgErr := app.config()
+		if cfgErr != nil {
+			return 0, 0, cfgErr
+		}
+		existing = cfg.Categories
+	} else if err != nil {
+		return 0, 0, err
+	}
+
+	merged, added := discovery.AddToConfig(existing, discovered)
+	if err := config.SaveCategories(path, merged); err != nil {
+		return 0, 0, err
+	}
+	return len(merged), added, nil
+}
+
+func navigate(page playwright.Page, url string) error {
+	_, err := page.Goto(url, playwright.PageGotoOptions{
+		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+		Timeout:   playwright.Float(30000),
+	})
+	return err
+}
+
+func countProducts(page playwright.Page, url string) int {
+	if err := navigate(page, url); err != nil {
+		slog.Warn("erro ao abrir categoria", "url", url, "error", err)
+		return 0
+	}
+
+	text, err := page.Evaluate("() => document.body.innerText")
+	if err != nil {
+		return 0
+	}
+
+	body, _ := text.(string)
+	n, _ := discovery.ProductCount(body)
+	return n
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vitor-labes/pc-scraper/internal/export"
+	"github.com/vitor-labes/pc-scraper/internal/queue"
+	"github.com/vitor-labes/pc-scraper/internal/scraper"
+)
+
+func runExport(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("export", "[flags]",
+		"Exporta a observação mais recente de cada produto gravado no banco para exports/products_<data>.csv.")
+	category := fs.String("category", "", "exportar apenas esta categoria")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	repo, err := openRepository(app)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	products, err := repo.LatestProducts(ctx, *category)
+	if err != nil {
+		return err
+	}
+
+	return export.ToCSV(products)
+}
+
+func runReplay(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("replay", "-file=<csv> [flags]",
+		"Republica na fila os produtos de um CSV gerado por scrape ou export, por exemplo\n"+
+			"quando a fila estava fora do ar durante a coleta.")
+	file := fs.String("file", "", "arquivo CSV exportado (obrigatório)")
+	dryRun := fs.Bool("dry-run", false, "apenas ler o arquivo, sem publicar")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usagef("-file é obrigatório")
+	}
+
+	products, err := export.ReadCSV(*file)
+	if err != nil {
+		return err
+	}
+	slog.Info("produtos lidos", "file", *file, "total", len(products))
+
+	if *dryRun {
+		return nil
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+
+	publisher, err := queue.NewPublisher(cfg.Queue, queue.NewRun(scraper.StoreName))
+	if err != nil {
+		return err
+	}
+	defer publisher.Close()
+
+	if published := publishProducts(ctx, publisher, products); published < len(products) {
+		return fmt.Errorf("%d de %d produtos não foram publicados", len(products)-published, len(products))
+	}
+	return nil
+}
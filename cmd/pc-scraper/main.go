@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// command is a subcommand; run receives the arguments after its name.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = []command{
+	{"scrape", "coleta produtos, publica na fila e exporta CSV", runScrape},
+	{"consume", "consome a fila e grava os produtos no banco", runConsume},
+	{"export", "exporta a última observação de cada produto para CSV", runExport},
+	{"query", "consultas ao banco (query best, query discounts)", runQuery},
+	{"stats", "mostra estatísticas do banco", runStats},
+	{"migrate", "aplica o schema SQL ao banco", runMigrate},
+	{"replay", "republica na fila os produtos de um CSV exportado", runReplay},
+	{"discover", "descobre categorias pelo menu e sitemap da loja", runDiscover},
+	{"config", "valida a configuração (config validate)", runConfig},
+}
+
+// app holds the global flags shared by every subcommand.
+type app struct {
+	configFile string
+	stdout     io.Writer
+}
+
+// config loads the configuration file (if any) with env overrides.
+func (a *app) config() (*config.Config, error) {
+	return config.Load(a.configFile)
+}
+
+// usageError marks invalid arguments; it exits with exitUsage.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	root := flag.NewFlagSet("pc-scraper", flag.ContinueOnError)
+	root.SetOutput(stderr)
+	configFile := root.String("config", os.Getenv("CONFIG_FILE"), "arquivo de configuração YAML ou TOML")
+	logLevel := root.String("log-level", envOr("LOG_LEVEL", "info"), "nível de log: debug, info, warn ou error")
+	logFormat := root.String("log-format", envOr("LOG_FORMAT", "text"), "formato de log: text ou json")
+	root.Usage = func() { printUsage(root) }
+
+	if err := root.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	rest := root.Args()
+	if len(rest) == 0 {
+		printUsage(root)
+		return exitUsage
+	}
+
+	name, cmdArgs := rest[0], rest[1:]
+	if name == "help" {
+		if len(cmdArgs) == 0 {
+			printUsage(root)
+			return exitOK
+		}
+		name, cmdArgs = cmdArgs[0], []string{"-h"}
+	}
+
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "comando desconhecido: %q\n\n", name)
+		printUsage(root)
+		return exitUsage
+	}
+
+	if err := setupLogging(stderr, *logLevel, *logFormat); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := cmd.run(ctx, &app{configFile: *configFile, stdout: stdout}, cmdArgs)
+
+	var usage usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usage):
+		fmt.Fprintf(stderr, "%v\nuse \"pc-scraper %s -h\" para ajuda\n", err, cmd.name)
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "erro: %v\n", err)
+		return exitError
+	}
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func printUsage(root *flag.FlagSet) {
+	out := root.Output()
+	fmt.Fprintln(out, "uso: pc-scraper [flags globais] <comando> [flags]")
+	fmt.Fprintln(out, "\nComandos:")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", c.name, c.summary)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nFlags globais:")
+	root.PrintDefaults()
+	fmt.Fprintln(out, "\nCódigos de saída: 0 sucesso, 1 erro de execução, 2 uso incorreto.")
+}
+
+// newFlagSet returns a flag set for a subcommand whose -h/--help prints
+// usage (the arguments synopsis) and the flags.
+func newFlagSet(name, usage, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "uso: pc-scraper %s %s\n\n%s\n", name, usage, description)
+
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(out, "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// parseFlags parses args, turning bad flags into usage errors. -h returns
+// flag.ErrHelp, which exits with success.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return usagef("argumentos inesperados: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+func setupLogging(w io.Writer, level, format string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("nível de log inválido %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		slog.SetDefault(slog.New(slog.NewTextHandler(w, opts)))
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(w, opts)))
+	default:
+		return fmt.Errorf("formato de log inválido %q (use text ou json)", format)
+	}
+	return nil
+}
+
+// serveMetrics starts the Prometheus endpoint in the background.
+func serveMetrics(port int) {
+	go func() {
+		addr := fmt.Sprintf(":%d", port)
+		slog.Info("iniciando servidor de métricas", "addr", addr)
+		if err := metrics.StartMetricsServer(addr); err != nil {
+			slog.Error("erro ao iniciar servidor de métricas", "error", err)
+		}
+	}()
+}
+
+func envOr(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/discovery"
+)
+
+func TestRunExitCodes(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, exitUsage},
+		{[]string{"-h"}, exitOK},
+		{[]string{"help"}, exitOK},
+		{[]string{"help", "scrape"}, exitOK},
+		{[]string{"desconhecido"}, exitUsage},
+		{[]string{"scrape", "--help"}, exitOK},
+		{[]string{"scrape", "-nope"}, exitUsage},
+		{[]string{"query"}, exitUsage},
+		{[]string{"query", "worst"}, exitUsage},
+		{[]string{"query", "best"}, exitUsage},
+		{[]string{"query", "discounts"}, exitUsage},
+		{[]string{"query", "discounts", "-category=GPU", "-days=0"}, exitUsage},
+		{[]string{"replay"}, exitUsage},
+		{[]string{"stats", "extra"}, exitUsage},
+		{[]string{"-log-format", "xml", "stats"}, exitUsage},
+		{[]string{"config", "validate"}, exitOK},
+		{[]string{"-config", "nope.yaml", "config", "validate"}, exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("exit = %d, want %d\nstderr: %s", got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestUpdateCategoriesFileSeedsFromConfig(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("CATEGORIES_FILE", "")
+	path := filepath.Join(t.TempDir(), "categories.json")
+
+	discovered := []discovery.Category{
+		{Name: "Placa De Video", URL: "https://www.pichau.com.br/hardware/placa-de-video"},
+		{Name: "Monitores", URL: "https://www.pichau.com.br/hardware/monitores"},
+	}
+	total, added, err := updateCategoriesFile(&app{}, path, discovered)
+	if err != nil {
+		t.Fatalf("updateCategoriesFile() erro: %v", err)
+	}
+
+	defaults := config.NewDefault().Categories
+	if added != 1 || total != len(defaults)+1 {
+		t.Errorf("added = %d, total = %d, want 1 e %d", added, total, len(defaults)+1)
+	}
+
+	written, err := config.LoadCategories(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gpu := written[0]; gpu.Name != "GPU" || gpu.Filter != "placa" || gpu.Kind != "gpu" {
+		t.Errorf("categoria da configuração perdeu filtro ou tipo: %+v", gpu)
+	}
+}
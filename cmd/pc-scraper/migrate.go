@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+func runMigrate(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("migrate", "[flags]",
+		"Aplica o schema ao banco em uma transação. O script é idempotente e pode ser\n"+
+			"executado novamente para adicionar colunas novas a bancos existentes.")
+	file := fs.String("file", "scripts/init.sql", "script SQL")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	script, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("erro ao ler script: %w", err)
+	}
+
+	repo, err := openRepository(app)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	if err := repo.Migrate(ctx, string(script)); err != nil {
+		return err
+	}
+
+	slog.Info("migração aplicada", "file", *file)
+	return nil
+}
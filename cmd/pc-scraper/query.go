@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/vitor-labes/pc-scraper/internal/repository"
+)
+
+func runQuery(ctx context.Context, app *app, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "-help" {
+		fs := newFlagSet("query", "<consulta> [flags]",
+			"Consultas:\n"+
+				"  best       produtos mais baratos (ou melhor avaliados) de uma categoria\n"+
+				"  discounts  desconto anunciado comparado ao desconto real sobre o histórico")
+		fs.Usage()
+		if len(args) == 0 {
+			return usagef("informe a consulta")
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "best":
+		return runQueryBest(ctx, app, args[1:])
+	case "discounts":
+		return runQueryDiscounts(ctx, app, args[1:])
+	default:
+		return usagef("consulta desconhecida: %q", args[0])
+	}
+}
+
+func runQueryBest(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("query best", "-category=<nome> [flags]",
+		"Lista os produtos novos e sem kit mais baratos de uma categoria. Com -rating-weight > 0,\n"+
+			"ordena por um preço efetivo que favorece produtos bem avaliados.")
+	category := fs.String("category", "", "categoria (obrigatória), ex.: GPU")
+	limit := fs.Int("limit", 20, "número máximo de produtos")
+	ratingWeight := fs.Float64("rating-weight", 0, "peso da avaliação no preço efetivo (ex.: 0.05 = até 5%)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *category == "" {
+		return usagef("-category é obrigatório")
+	}
+	if *limit < 1 {
+		return usagef("-limit deve ser positivo")
+	}
+
+	repo, err := openRepository(app)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	w := tabwriter.NewWriter(app.stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	defer w.Flush()
+
+	if *ratingWeight > 0 {
+		ranked, err := repo.FindBestRated(ctx, *category, *ratingWeight, *limit)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "PREÇO\tEFETIVO\tNOTA\tAVALIAÇÕES\tPRODUTO\t")
+		for _, p := range ranked {
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t%d\t%s\t\n",
+				p.Price, p.EffectivePrice, p.WeightedRating, p.ReviewCount, p.Title)
+		}
+		return nil
+	}
+
+	products, err := repo.FindBestPrices(ctx, *category, *limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "PREÇO\tPRODUTO\t")
+	for _, p := range products {
+		fmt.Fprintf(w, "%s\t%s\t\n", p.Price, p.Title)
+	}
+	return nil
+}
+
+func runQueryDiscounts(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("query discounts", "-category=<nome> [flags]",
+		"Compara o desconto anunciado das promoções atuais de uma categoria com o desconto real\n"+
+			"sobre a mediana dos preços observados nos últimos -days dias.")
+	category := fs.String("category", "", "categoria (obrigatória), ex.: GPU")
+	days := fs.Int("days", 30, "janela do histórico de preços, em dias")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *category == "" {
+		return usagef("-category é obrigatório")
+	}
+	if *days < 1 {
+		return usagef("-days deve ser positivo")
+	}
+
+	repo, err := openRepository(app)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	checks, err := repo.DiscountChecks(ctx, *category, *days)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(app.stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "PREÇO\tORIGINAL\tREFERÊNCIA\tANUNCIADO\tREAL\tCUPOM\tPRODUTO\t")
+	for _, c := range checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f%%\t%.2f%%\t%s\t%s\t\n",
+			c.Price, c.OriginalPrice, c.ReferencePrice, c.AdvertisedDiscount, c.RealDiscount, c.CouponCode, c.Title)
+	}
+	return nil
+}
+
+func runStats(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("stats", "", "Mostra totais e faixa de preços dos produtos gravados.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	repo, err := openRepository(app)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	stats, err := repo.GetStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(app.stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "produtos\t%v\n", stats["total_products"])
+	fmt.Fprintf(w, "categorias\t%v\n", stats["categories"])
+	fmt.Fprintf(w, "menor preço\t%s\n", stats["min_price"])
+	fmt.Fprintf(w, "maior preço\t%s\n", stats["max_price"])
+	fmt.Fprintf(w, "preço médio\t%s\n", stats["avg_price"])
+	return nil
+}
+
+func openRepository(app *app) (*repository.ProductRepository, error) {
+	cfg, err := app.config()
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewProductRepository(cfg.Database.DSN())
+}
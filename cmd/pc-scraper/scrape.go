@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+	"github.com/vitor-labes/pc-scraper/internal/export"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
+	"github.com/vitor-labes/pc-scraper/internal/queue"
+	"github.com/vitor-labes/pc-scraper/internal/scraper"
+)
+
+// publishTimeout bounds publishing once the scrape itself has ended, even
+// when it ended because the run deadline passed or a signal arrived.
+const publishTimeout = 2 * time.Minute
+
+func runScrape(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("scrape", "[flags]",
+		"Coleta as categorias configuradas, publica os produtos na fila e exporta um CSV em exports/.")
+	publish := fs.Bool("publish", true, "publicar os produtos na fila")
+	exportCSV := fs.Bool("export", true, "exportar os produtos para CSV")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+
+	serveMetrics(cfg.Metrics.ScraperPort)
+
+	run := queue.NewRun(scraper.StoreName)
+
+	for _, cat := range cfg.Categories {
+		if len(cat.Targets) > 0 {
+			slog.Info("alvos configurados",
+				"category", cat.Name,
+				"targets", cat.Targets,
+				"search", cat.SearchTargets,
+			)
+		}
+	}
+
+	slog.Info("iniciando scraper",
+		"run_id", run.ID,
+		"version", queue.ProducerVersion,
+		"config", app.configFile,
+		"categories", len(cfg.Categories),
+		"max_pages", cfg.MaxPages,
+		"run_timeout", cfg.RunTimeout,
+		"headless", cfg.Headless,
+		"engine", cfg.BrowserEngine,
+		"incremental", cfg.Incremental,
+		"queue", cfg.Queue.Name,
+	)
+
+	// Connect RabbitMQ before scraping so a broken queue fails fast.
+	var publisher *queue.Publisher
+	if *publish {
+		publisher, err = queue.NewPublisher(cfg.Queue, run)
+		if err != nil {
+			return err
+		}
+		defer publisher.Close()
+	}
+
+	// Timeout, split between categories by the scraper
+	runCtx, cancel := ctx, context.CancelFunc(func() {})
+	if cfg.RunTimeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, cfg.RunTimeout)
+	}
+	defer cancel()
+
+	// Execute
+	products, err := scraper.NewPichauScraper(cfg, watchConfig(runCtx, app, cfg)...).Scrape(runCtx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("scraping concluído",
+		"total_products_found", len(products),
+	)
+
+	// The CSV is written even when publishing failed, so it can be replayed.
+	var errs []error
+	if publisher != nil {
+		publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+		defer cancel()
+		if published := publishProducts(publishCtx, publisher, products); published < len(products) {
+			errs = append(errs, fmt.Errorf("%d de %d produtos não foram publicados", len(products)-published, len(products)))
+		}
+	}
+
+	if *exportCSV {
+		if len(products) == 0 {
+			slog.Warn("nenhum produto para exportar")
+		} else {
+			slog.Info("gerando arquivo CSV...")
+			if err := export.ToCSV(products); err != nil {
+				errs = append(errs, fmt.Errorf("erro ao exportar CSV: %w", err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// watchConfig starts hot reload of the config file, when there is one, and
+// returns the scraper options that apply it.
+func watchConfig(ctx context.Context, app *app, cfg *config.Config) []scraper.Option {
+	metrics.ConfigVersion.Set(1)
+	if app.configFile == "" || cfg.ReloadInterval <= 0 {
+		return nil
+	}
+
+	watcher := config.NewWatcher(app.configFile, cfg)
+	watcher.OnReload = func(next *config.Config, version int64, checksum string) {
+		metrics.ConfigVersion.Set(float64(version))
+		metrics.ConfigReloads.WithLabelValues("applied").Inc()
+		slog.Info("configuração recarregada",
+			"version", version,
+			"checksum", checksum,
+			"categories", len(next.Categories),
+		)
+	}
+	watcher.OnReject = func(err error) {
+		metrics.ConfigReloads.WithLabelValues("rejected").Inc()
+		slog.Error("configuração rejeitada, mantendo a anterior",
+			"version", watcher.Version(),
+			"error", err,
+		)
+	}
+
+	slog.Info("observando arquivo de configuração",
+		"path", app.configFile,
+		"interval", cfg.ReloadInterval,
+		"version", watcher.Version(),
+		"checksum", watcher.Checksum(),
+	)
+	go watcher.Run(ctx, cfg.ReloadInterval)
+
+	return []scraper.Option{scraper.WithConfigSource(watcher.Current)}
+}
+
+// publishProducts publishes every product, logging failures, and returns
+// how many the broker confirmed.
+func publishProducts(ctx context.Context, publisher *queue.Publisher, products []domain.Product) int {
+	type sent struct {
+		product      domain.Product
+		confirmation *queue.Confirmation
+	}
+
+	inFlight := make([]sent, 0, len(products))
+	for _, product := range products {
+		confirmation, err := publisher.Publish(ctx, product)
+		if err != nil {
+			slog.Error("erro ao publicar produto",
+				"title", product.Title,
+				"error", err,
+			)
+			continue
+		}
+		inFlight = append(inFlight, sent{product, confirmation})
+	}
+
+	confirmed := 0
+	for _, s := range inFlight {
+		if err := s.confirmation.Wait(ctx); err != nil {
+			slog.Error("produto não confirmado pelo broker",
+				"title", s.product.Title,
+				"message_id", s.confirmation.MessageID,
+				"error", err,
+			)
+			continue
+		}
+		confirmed++
+	}
+
+	slog.Info("publicação finalizada",
+		"total_published", confirmed,
+		"failed", len(products)-confirmed,
+	)
+	return confirmed
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadCategories reads a JSON array of categories, as written by the
+// discovery command.
+func LoadCategories(path string) ([]CategoryConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de categorias: %w", err)
+	}
+
+	var categories []CategoryConfig
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar categorias de %s: %w", path, err)
+	}
+
+	return categories, nil
+}
+
+func SaveCategories(path string, categories []CategoryConfig) error {
+	data, err := json.MarshalIndent(categories, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar categorias: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("erro ao gravar arquivo de categorias: %w", err)
+	}
+
+	return nil
+}
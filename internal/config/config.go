@@ -2,23 +2,167 @@ package config
 
 import "time"
 
+// Config holds every setting of the scraper and consumer. It is built from
+// NewDefault, optionally overlaid with a YAML or TOML file and environment
+// variables (see Load).
 type Config struct {
-	MaxPages       int
-	WaitTimeMin    time.Duration
-	WaitTimeMax    time.Duration
-	PageDelay      time.Duration
-	Headless       bool
-	UserAgent      string
-	Categories     []CategoryConfig
-	CloudflareWait time.Duration
-	RetryAttempts  int
+	MaxPages       int                  `yaml:"max_pages" toml:"max_pages"`
+	WaitTimeMin    time.Duration        `yaml:"wait_time_min" toml:"wait_time_min"`
+	WaitTimeMax    time.Duration        `yaml:"wait_time_max" toml:"wait_time_max"`
+	PageDelay      time.Duration        `yaml:"page_delay" toml:"page_delay"`
+	Headless       bool                 `yaml:"headless" toml:"headless"`
+	UserAgent      string               `yaml:"user_agent" toml:"user_agent"`
+	BrowserEngine  string               `yaml:"browser_engine" toml:"browser_engine"`
+	Fingerprints   []FingerprintProfile `yaml:"fingerprints" toml:"fingerprints"`
+	Categories     []CategoryConfig     `yaml:"categories" toml:"categories"`
+	Stores         []StoreConfig        `yaml:"stores" toml:"stores"`
+	CloudflareWait time.Duration        `yaml:"cloudflare_wait" toml:"cloudflare_wait"`
+	RetryAttempts  int                  `yaml:"retry_attempts" toml:"retry_attempts"`
+
+	// NavigationTimeout bounds each page load. RunTimeout is the deadline of
+	// a whole scrape (0 disables it); it is split between the categories
+	// still to run so a slow one cannot starve the rest.
+	NavigationTimeout time.Duration `yaml:"navigation_timeout" toml:"navigation_timeout"`
+	RunTimeout        time.Duration `yaml:"run_timeout" toml:"run_timeout"`
+
+	// PageRecycleEvery opens a fresh page after this many navigations (0 disables).
+	PageRecycleEvery int `yaml:"page_recycle_every" toml:"page_recycle_every"`
+
+	// Incremental stops paging a listing after IncrementalStopAfter pages
+	// without new products or price changes; a full run still happens every
+	// FullRunInterval. Observations are kept in StateFile.
+	Incremental          bool          `yaml:"incremental" toml:"incremental"`
+	IncrementalStopAfter int           `yaml:"incremental_stop_after" toml:"incremental_stop_after"`
+	FullRunInterval      time.Duration `yaml:"full_run_interval" toml:"full_run_interval"`
+	StateFile            string        `yaml:"state_file" toml:"state_file"`
+
+	LightweightBrowser   bool     `yaml:"lightweight_browser" toml:"lightweight_browser"`
+	BlockResources       bool     `yaml:"block_resources" toml:"block_resources"`
+	BlockedResourceTypes []string `yaml:"blocked_resource_types" toml:"blocked_resource_types"`
+	BlockedURLPatterns   []string `yaml:"blocked_url_patterns" toml:"blocked_url_patterns"`
+
+	// ReloadInterval is how often the configuration file is checked for
+	// changes (0 disables hot reload).
+	ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval"`
+
+	Queue    QueueConfig    `yaml:"queue" toml:"queue"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Metrics  MetricsConfig  `yaml:"metrics" toml:"metrics"`
+}
+
+// QueueConfig is the RabbitMQ connection. URL, when set, is used as is;
+// otherwise the URL is composed from the separate fields (see DialURL).
+// Passwords can be read from PasswordFile, e.g. a Docker or Kubernetes secret.
+// MaxInFlight caps how many published messages may await the broker's
+// confirmation at once.
+type QueueConfig struct {
+	URL          string    `yaml:"url" toml:"url"`
+	Name         string    `yaml:"name" toml:"name"`
+	Host         string    `yaml:"host" toml:"host"`
+	Port         int       `yaml:"port" toml:"port"`
+	User         string    `yaml:"user" toml:"user"`
+	Password     string    `yaml:"password" toml:"password"`
+	PasswordFile string    `yaml:"password_file" toml:"password_file"`
+	VHost        string    `yaml:"vhost" toml:"vhost"`
+	TLS          TLSConfig `yaml:"tls" toml:"tls"`
+	MaxInFlight  int       `yaml:"max_in_flight" toml:"max_in_flight"`
+}
+
+// TLSConfig enables AMQPS. CAFile adds a CA to the system pool; CertFile
+// and KeyFile present a client certificate.
+type TLSConfig struct {
+	Enabled    bool   `yaml:"enabled" toml:"enabled"`
+	CAFile     string `yaml:"ca_file" toml:"ca_file"`
+	CertFile   string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile    string `yaml:"key_file" toml:"key_file"`
+	ServerName string `yaml:"server_name" toml:"server_name"`
+}
+
+// DatabaseConfig is the PostgreSQL connection. URL, when set, is used as
+// is; otherwise the DSN is composed from the separate fields (see DSN).
+// SSLMode and the SSL files follow libpq: verify-full checks both the
+// certificate chain and the host name.
+type DatabaseConfig struct {
+	URL          string `yaml:"url" toml:"url"`
+	Host         string `yaml:"host" toml:"host"`
+	Port         int    `yaml:"port" toml:"port"`
+	User         string `yaml:"user" toml:"user"`
+	Password     string `yaml:"password" toml:"password"`
+	PasswordFile string `yaml:"password_file" toml:"password_file"`
+	Name         string `yaml:"name" toml:"name"`
+	SSLMode      string `yaml:"sslmode" toml:"sslmode"`
+	SSLRootCert  string `yaml:"sslrootcert" toml:"sslrootcert"`
+	SSLCert      string `yaml:"sslcert" toml:"sslcert"`
+	SSLKey       string `yaml:"sslkey" toml:"sslkey"`
+}
+
+// MetricsConfig sets the port of each process's Prometheus endpoint.
+type MetricsConfig struct {
+	ScraperPort  int `yaml:"scraper_port" toml:"scraper_port"`
+	ConsumerPort int `yaml:"consumer_port" toml:"consumer_port"`
+}
+
+// FingerprintProfile is a consistent set of browser traits applied to a
+// browser context. Profiles are only used with the engine they describe.
+type FingerprintProfile struct {
+	Name           string `yaml:"name" toml:"name"`
+	Engine         string `yaml:"engine" toml:"engine"`
+	UserAgent      string `yaml:"user_agent" toml:"user_agent"`
+	ViewportWidth  int    `yaml:"viewport_width" toml:"viewport_width"`
+	ViewportHeight int    `yaml:"viewport_height" toml:"viewport_height"`
+	Locale         string `yaml:"locale" toml:"locale"`
+	TimezoneID     string `yaml:"timezone_id" toml:"timezone_id"`
+	Platform       string `yaml:"platform" toml:"platform"`
+}
+
+type StoreConfig struct {
+	Name string `yaml:"name" toml:"name"`
+	// Behavior is the human-simulation profile: none, light or realistic.
+	Behavior string `yaml:"behavior" toml:"behavior"`
+	// SearchURL is the store search page with a {query} placeholder.
+	SearchURL string `yaml:"search_url" toml:"search_url"`
+	// Query parameter names used by the store's listing pages.
+	PageParam     string `yaml:"page_param" toml:"page_param"`
+	SortParam     string `yaml:"sort_param" toml:"sort_param"`
+	PageSizeParam string `yaml:"page_size_param" toml:"page_size_param"`
 }
 
 type CategoryConfig struct {
-	Name    string
-	URL     string
-	Filter  string
-	Targets []string
+	Name    string   `json:"name" yaml:"name" toml:"name"`
+	URL     string   `json:"url" yaml:"url" toml:"url"`
+	Filter  string   `json:"filter,omitempty" yaml:"filter" toml:"filter"`
+	Targets []string `json:"targets,omitempty" yaml:"targets" toml:"targets"`
+	// Kind selects the title parser: gpu, cpu, motherboard, ram, ssd, psu
+	// or case. Empty disables attribute extraction.
+	Kind string `json:"kind,omitempty" yaml:"kind" toml:"kind"`
+	// SearchTargets scrapes one store search per target instead of paging
+	// through the whole category.
+	SearchTargets bool `json:"search_targets,omitempty" yaml:"search_targets" toml:"search_targets"`
+	// Sort is the store's sort value (e.g. "price_asc"), Facets are extra
+	// filter parameters and PageSize the number of items per page.
+	Sort     string              `json:"sort,omitempty" yaml:"sort" toml:"sort"`
+	Facets   map[string][]string `json:"facets,omitempty" yaml:"facets" toml:"facets"`
+	PageSize int                 `json:"page_size,omitempty" yaml:"page_size" toml:"page_size"`
+	// Conditions lists the accepted conditions (new, open_box, refurbished);
+	// empty accepts all. ExcludeBundles drops kits and combos.
+	Conditions     []string `json:"conditions,omitempty" yaml:"conditions" toml:"conditions"`
+	ExcludeBundles bool     `json:"exclude_bundles,omitempty" yaml:"exclude_bundles" toml:"exclude_bundles"`
+	// Overrides of the global settings; zero keeps the global value.
+	// TimeBudget caps the category's share of the run deadline.
+	MaxPages          int           `json:"max_pages,omitempty" yaml:"max_pages" toml:"max_pages"`
+	WaitTimeMin       time.Duration `json:"wait_time_min,omitempty" yaml:"wait_time_min" toml:"wait_time_min"`
+	WaitTimeMax       time.Duration `json:"wait_time_max,omitempty" yaml:"wait_time_max" toml:"wait_time_max"`
+	NavigationTimeout time.Duration `json:"navigation_timeout,omitempty" yaml:"navigation_timeout" toml:"navigation_timeout"`
+	TimeBudget        time.Duration `json:"time_budget,omitempty" yaml:"time_budget" toml:"time_budget"`
+}
+
+// CategoryLimits are the paging settings in effect for one category.
+type CategoryLimits struct {
+	MaxPages          int
+	WaitTimeMin       time.Duration
+	WaitTimeMax       time.Duration
+	NavigationTimeout time.Duration
+	TimeBudget        time.Duration
 }
 
 func NewDefault() *Config {
@@ -29,19 +173,211 @@ func NewDefault() *Config {
 		PageDelay:      10 * time.Second,
 		Headless:       false,
 		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36",
+		BrowserEngine:  "chromium",
+		Fingerprints:   defaultFingerprints(),
 		CloudflareWait: 30 * time.Second,
 		RetryAttempts:  3,
+
+		NavigationTimeout: 30 * time.Second,
+		RunTimeout:        30 * time.Minute,
+
+		PageRecycleEvery: 25,
+
+		Incremental:          false,
+		IncrementalStopAfter: 2,
+		FullRunInterval:      24 * time.Hour,
+		StateFile:            "state/observations.json",
+
+		LightweightBrowser:   true,
+		BlockResources:       true,
+		BlockedResourceTypes: []string{"image", "media", "font"},
+		BlockedURLPatterns: []string{
+			"google-analytics.com",
+			"googletagmanager.com",
+			"doubleclick.net",
+			"googleadservices.com",
+			"facebook.net",
+			"connect.facebook.com",
+			"hotjar.com",
+			"clarity.ms",
+			"analytics.tiktok.com",
+			"criteo.com",
+			"taboola.com",
+		},
+		ReloadInterval: 10 * time.Second,
+
+		Queue: QueueConfig{
+			Name:        "product_prices",
+			Host:        "localhost",
+			User:        "scraper",
+			MaxInFlight: 64,
+		},
+		Database: DatabaseConfig{
+			Host:    "localhost",
+			Port:    5432,
+			User:    "scraper",
+			Name:    "products",
+			SSLMode: "verify-full",
+		},
+		Metrics: MetricsConfig{
+			ScraperPort:  2114,
+			ConsumerPort: 2113,
+		},
+		Stores: []StoreConfig{
+			{
+				Name:          "pichau",
+				Behavior:      "light",
+				SearchURL:     "https://www.pichau.com.br/search?q={query}",
+				PageParam:     "page",
+				SortParam:     "sort",
+				PageSizeParam: "limit",
+			},
+		},
 		Categories: []CategoryConfig{
 			{
 				Name:   "GPU",
 				URL:    "https://www.pichau.com.br/hardware/placa-de-video",
 				Filter: "placa",
+				Kind:   "gpu",
 			},
 			{
 				Name:   "CPU",
 				URL:    "https://www.pichau.com.br/hardware/processadores",
 				Filter: "processador",
+				Kind:   "cpu",
 			},
+			{
+				Name:   "MOTHERBOARD",
+				URL:    "https://www.pichau.com.br/hardware/placa-m-e",
+				Filter: "placa",
+				Kind:   "motherboard",
+			},
+			{
+				Name:   "RAM",
+				URL:    "https://www.pichau.com.br/hardware/memorias",
+				Filter: "mem",
+				Kind:   "ram",
+			},
+			{
+				Name:   "SSD",
+				URL:    "https://www.pichau.com.br/hardware/ssd",
+				Filter: "ssd",
+				Kind:   "ssd",
+			},
+			{
+				Name:   "PSU",
+				URL:    "https://www.pichau.com.br/hardware/fonte",
+				Filter: "fonte",
+				Kind:   "psu",
+			},
+			{
+				Name:   "CASE",
+				URL:    "https://www.pichau.com.br/hardware/gabinete",
+				Filter: "gabinete",
+				Kind:   "case",
+			},
+		},
+	}
+}
+
+// Store returns the settings of the named store, or a zero StoreConfig with
+// only the name set when it is not configured.
+func (c *Config) Store(name string) StoreConfig {
+	for _, store := range c.Stores {
+		if store.Name == name {
+			return store
+		}
+	}
+	return StoreConfig{Name: name}
+}
+
+// Limits resolves the category's overrides against the global settings.
+func (c *Config) Limits(category CategoryConfig) CategoryLimits {
+	limits := CategoryLimits{
+		MaxPages:          c.MaxPages,
+		WaitTimeMin:       c.WaitTimeMin,
+		WaitTimeMax:       c.WaitTimeMax,
+		NavigationTimeout: c.NavigationTimeout,
+		TimeBudget:        category.TimeBudget,
+	}
+
+	if category.MaxPages > 0 {
+		limits.MaxPages = category.MaxPages
+	}
+	if category.WaitTimeMin > 0 {
+		limits.WaitTimeMin = category.WaitTimeMin
+	}
+	if category.WaitTimeMax > 0 {
+		limits.WaitTimeMax = category.WaitTimeMax
+	}
+	if category.NavigationTimeout > 0 {
+		limits.NavigationTimeout = category.NavigationTimeout
+	}
+
+	return limits
+}
+
+func defaultFingerprints() []FingerprintProfile {
+	return []FingerprintProfile{
+		{
+			Name:           "chrome-windows-fhd",
+			Engine:         "chromium",
+			UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36",
+			ViewportWidth:  1920,
+			ViewportHeight: 1080,
+			Locale:         "pt-BR",
+			TimezoneID:     "America/Sao_Paulo",
+			Platform:       "Win32",
+		},
+		{
+			Name:           "chrome-windows-laptop",
+			Engine:         "chromium",
+			UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			ViewportWidth:  1366,
+			ViewportHeight: 768,
+			Locale:         "pt-BR",
+			TimezoneID:     "America/Sao_Paulo",
+			Platform:       "Win32",
+		},
+		{
+			Name:           "chrome-macos",
+			Engine:         "chromium",
+			UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			ViewportWidth:  1440,
+			ViewportHeight: 900,
+			Locale:         "pt-BR",
+			TimezoneID:     "America/Sao_Paulo",
+			Platform:       "MacIntel",
+		},
+		{
+			Name:           "firefox-windows",
+			Engine:         "firefox",
+			UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0",
+			ViewportWidth:  1920,
+			ViewportHeight: 1080,
+			Locale:         "pt-BR",
+			TimezoneID:     "America/Sao_Paulo",
+			Platform:       "Win32",
+		},
+		{
+			Name:           "firefox-linux",
+			Engine:         "firefox",
+			UserAgent:      "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+			ViewportWidth:  1600,
+			ViewportHeight: 900,
+			Locale:         "pt-BR",
+			TimezoneID:     "America/Sao_Paulo",
+			Platform:       "Linux x86_64",
+		},
+		{
+			Name:           "safari-macos",
+			Engine:         "webkit",
+			UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+			ViewportWidth:  1440,
+			ViewportHeight: 900,
+			Locale:         "pt-BR",
+			TimezoneID:     "America/Sao_Paulo",
+			Platform:       "MacIntel",
 		},
 	}
 }
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := NewDefault().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+}
+
+func TestLoadFileFormats(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+max_pages: 3
+wait_time_min: 1s
+wait_time_max: 2s
+queue:
+  name: prices
+categories:
+  - name: GPU
+    url: https://example.com/gpu
+    targets: [RTX 4060]
+`,
+		"config.toml": `
+max_pages = 3
+wait_time_min = "1s"
+wait_time_max = "2s"
+
+[queue]
+name = "prices"
+
+[[categories]]
+name = "GPU"
+url = "https://example.com/gpu"
+targets = ["RTX 4060"]
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			cfg := NewDefault()
+			if err := LoadFile(cfg, writeFile(t, name, content)); err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.MaxPages != 3 || cfg.WaitTimeMin != time.Second || cfg.WaitTimeMax != 2*time.Second {
+				t.Errorf("scalars = %d %s %s", cfg.MaxPages, cfg.WaitTimeMin, cfg.WaitTimeMax)
+			}
+			if cfg.Queue.Name != "prices" || cfg.Queue.URL != NewDefault().Queue.URL {
+				t.Errorf("queue = %+v, want name overridden and URL kept", cfg.Queue)
+			}
+			if len(cfg.Categories) != 1 || cfg.Categories[0].Targets[0] != "RTX 4060" {
+				t.Errorf("categories = %+v", cfg.Categories)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadFileRejectsUnknownKeys(t *testing.T) {
+	for name, content := range map[string]string{
+		"config.yaml": "max_page: 3\n",
+		"config.toml": "max_page = 3\n",
+	} {
+		err := LoadFile(NewDefault(), writeFile(t, name, content))
+		if err == nil || !strings.Contains(err.Error(), "max_page") {
+			t.Errorf("%s: error = %v, want unknown key max_page", name, err)
+		}
+	}
+}
+
+func TestApplyEnvOverridesFile(t *testing.T) {
+	cfg := NewDefault()
+	if err := LoadFile(cfg, writeFile(t, "config.yaml", "max_pages: 3\nheadless: false\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"MAX_PAGES":      "7",
+		"HEADLESS":       "true",
+		"GPU_TARGETS":    "RTX 4060,RX 7600",
+		"SEARCH_TARGETS": "true",
+	}
+	if err := applyEnv(cfg, func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.MaxPages != 7 || !cfg.Headless {
+		t.Errorf("MaxPages=%d Headless=%v, want env values", cfg.MaxPages, cfg.Headless)
+	}
+	for _, cat := range cfg.Categories {
+		want := cat.Name == "GPU"
+		if cat.SearchTargets != want {
+			t.Errorf("%s.SearchTargets = %v, want %v", cat.Name, cat.SearchTargets, want)
+		}
+	}
+}
+
+func TestApplyEnvReportsInvalidValues(t *testing.T) {
+	env := map[string]string{"MAX_PAGES": "cinco", "PAGE_DELAY": "10"}
+	err := applyEnv(NewDefault(), func(k string) string { return env[k] })
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, key := range []string{"MAX_PAGES", "PAGE_DELAY"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q does not mention %s", err, key)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string
+	}{
+		{"wait window", func(c *Config) { c.WaitTimeMin = 10 * time.Second }, "wait_time_min (10s) maior que wait_time_max (9s)"},
+		{"max pages", func(c *Config) { c.MaxPages = 0 }, "max_pages"},
+		{"engine", func(c *Config) { c.BrowserEngine = "opera" }, "browser_engine"},
+		{"kind", func(c *Config) { c.Categories[0].Kind = "monitor" }, "categories[GPU].kind"},
+		{"condition", func(c *Config) { c.Categories[0].Conditions = []string{"used"} }, "categories[GPU].conditions"},
+		{"duplicate category", func(c *Config) { c.Categories[1].Name = "gpu" }, "categoria duplicada"},
+		{"filter case", func(c *Config) { c.Categories[0].Filter = "Placa" }, `categories[GPU].filter deve estar em minúsculas (atual: "Placa", use "placa")`},
+		{"search without targets", func(c *Config) { c.Categories[0].SearchTargets = true }, "search_targets exige targets"},
+		{"queue scheme", func(c *Config) { c.Queue.URL = "http://localhost:5672/" }, "queue.url"},
+		{"queue tls scheme", func(c *Config) { c.Queue.URL = "amqp://h/"; c.Queue.TLS.Enabled = true }, "amqps://"},
+		{"queue tls files", func(c *Config) { c.Queue.TLS = TLSConfig{Enabled: true, CAFile: "/nope/ca.pem"} }, "queue.tls: arquivo inacessível"},
+		{"client cert without key", func(c *Config) { c.Database.SSLCert = "config_test.go" }, "juntos"},
+		{"sslmode", func(c *Config) { c.Database.SSLMode = "prefer" }, "database.sslmode"},
+		{"metrics port", func(c *Config) { c.Metrics.ScraperPort = 70000 }, "metrics.scraper_port"},
+		{"category wait window", func(c *Config) { c.Categories[0].WaitTimeMin = 20 * time.Second }, "categories[GPU]: wait_time_min (20s) maior que wait_time_max (9s)"},
+		{"category budget", func(c *Config) { c.Categories[0].TimeBudget = time.Hour }, "categories[GPU].time_budget"},
+		{"category negative", func(c *Config) { c.Categories[0].MaxPages = -1 }, "categories[GPU]: max_pages"},
+		{"navigation timeout", func(c *Config) { c.NavigationTimeout = 0 }, "navigation_timeout"},
+		{"store search url", func(c *Config) { c.Stores[0].SearchURL = "https://example.com/search" }, "{query}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefault()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDoesNotLeakPasswords(t *testing.T) {
+	cfg := NewDefault()
+	cfg.Database.URL = "postgres://user:s3cret@/db"
+
+	err := cfg.Validate()
+	if err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("Validate() = %v, want error without password", err)
+	}
+}
+
+func TestLimitsResolveOverrides(t *testing.T) {
+	cfg := NewDefault()
+
+	if got := cfg.Limits(CategoryConfig{Name: "CPU"}); got.MaxPages != cfg.MaxPages ||
+		got.WaitTimeMin != cfg.WaitTimeMin || got.NavigationTimeout != cfg.NavigationTimeout {
+		t.Errorf("Limits() sem overrides = %+v, want globais", got)
+	}
+
+	got := cfg.Limits(CategoryConfig{
+		Name:              "GPU",
+		MaxPages:          12,
+		WaitTimeMax:       20 * time.Second,
+		NavigationTimeout: time.Minute,
+		TimeBudget:        10 * time.Minute,
+	})
+	want := CategoryLimits{
+		MaxPages:          12,
+		WaitTimeMin:       cfg.WaitTimeMin,
+		WaitTimeMax:       20 * time.Second,
+		NavigationTimeout: time.Minute,
+		TimeBudget:        10 * time.Minute,
+	}
+	if got != want {
+		t.Errorf("Limits() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DialURL returns URL when set, otherwise an amqp:// (or amqps:// with TLS)
+// URL composed from the separate fields with the credentials escaped. A zero
+// port means the scheme's default, 5672 or 5671.
+func (q QueueConfig) DialURL() string {
+	if q.URL != "" {
+		return q.URL
+	}
+
+	scheme, port := "amqp", q.Port
+	if q.TLS.Enabled {
+		scheme = "amqps"
+	}
+	if port == 0 {
+		port = 5672
+		if q.TLS.Enabled {
+			port = 5671
+		}
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(q.Host, strconv.Itoa(port)),
+		Path:   "/",
+	}
+	if q.User != "" {
+		u.User = url.UserPassword(q.User, q.Password)
+	}
+	if q.VHost != "" && q.VHost != "/" {
+		u.Path = "/" + q.VHost
+		u.RawPath = "/" + url.PathEscape(q.VHost)
+	}
+	return u.String()
+}
+
+// ClientConfig builds the TLS settings for AMQPS, or nil when disabled.
+func (t TLSConfig) ClientConfig() (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: t.ServerName,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler CA: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("nenhum certificado válido em %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar certificado do cliente: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// DSN returns URL when set, otherwise a postgres:// URL composed from the
+// separate fields with the credentials escaped and the SSL settings as
+// parameters.
+func (d DatabaseConfig) DSN() string {
+	if d.URL != "" {
+		return d.URL
+	}
+
+	port := d.Port
+	if port == 0 {
+		port = 5432
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   net.JoinHostPort(d.Host, strconv.Itoa(port)),
+		Path:   "/" + d.Name,
+	}
+	if d.User != "" {
+		u.User = url.UserPassword(d.User, d.Password)
+	}
+
+	params := url.Values{}
+	params.Set("sslmode", d.SSLMode)
+	for name, value := range map[string]string{
+		"sslrootcert": d.SSLRootCert,
+		"sslcert":     d.SSLCert,
+		"sslkey":      d.SSLKey,
+	} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	u.RawQuery = params.Encode()
+
+	return u.String()
+}
+
+// loadSecrets reads the password files, which take precedence over inline
+// passwords. Trailing newlines, common in secret files, are dropped.
+func (c *Config) loadSecrets() error {
+	for _, s := range []struct {
+		file string
+		dst  *string
+	}{
+		{c.Queue.PasswordFile, &c.Queue.Password},
+		{c.Database.PasswordFile, &c.Database.Password},
+	} {
+		if s.file == "" {
+			continue
+		}
+
+		secret, err := readSecret(s.file)
+		if err != nil {
+			return err
+		}
+		*s.dst = secret
+	}
+	return nil
+}
+
+func readSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler segredo: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
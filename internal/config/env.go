@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ApplyEnv overlays environment variables onto cfg. Empty variables are
+// ignored; values that do not parse are reported together.
+//
+// Global: CATEGORIES_FILE, MAX_PAGES, WAIT_TIME_MIN, WAIT_TIME_MAX,
+// PAGE_DELAY, HEADLESS, USER_AGENT, BROWSER_ENGINE, CLOUDFLARE_WAIT,
+// RETRY_ATTEMPTS, NAVIGATION_TIMEOUT, RUN_TIMEOUT, INCREMENTAL, STATE_FILE, LIGHTWEIGHT_BROWSER,
+// BLOCK_RESOURCES, CONFIG_RELOAD_INTERVAL, SEARCH_TARGETS,
+// SCRAPER_METRICS_PORT and CONSUMER_METRICS_PORT.
+//
+// Queue: RABBITMQ_URL, QUEUE_NAME, RABBITMQ_HOST, RABBITMQ_PORT,
+// RABBITMQ_USER, RABBITMQ_PASSWORD, RABBITMQ_PASSWORD_FILE, RABBITMQ_VHOST,
+// RABBITMQ_TLS, RABBITMQ_CA_FILE, RABBITMQ_CERT_FILE, RABBITMQ_KEY_FILE,
+// RABBITMQ_SERVER_NAME and RABBITMQ_MAX_IN_FLIGHT.
+//
+// Database: DATABASE_URL, DATABASE_HOST, DATABASE_PORT, DATABASE_USER,
+// DATABASE_PASSWORD, DATABASE_PASSWORD_FILE, DATABASE_NAME,
+// DATABASE_SSLMODE, DATABASE_SSLROOTCERT, DATABASE_SSLCERT and
+// DATABASE_SSLKEY. RABBITMQ_URL and DATABASE_URL also accept a _FILE
+// variant holding the URL.
+//
+// Per category, prefixed with EnvKey(name): _FILTER, _TARGETS,
+// _CONDITIONS, _EXCLUDE_BUNDLES, _MAX_PAGES and _TIME_BUDGET.
+func ApplyEnv(cfg *Config) error {
+	return applyEnv(cfg, os.Getenv)
+}
+
+func applyEnv(cfg *Config, getenv func(string) string) error {
+	e := envReader{getenv: getenv}
+
+	if path := getenv("CATEGORIES_FILE"); path != "" {
+		categories, err := LoadCategories(path)
+		if err != nil {
+			return err
+		}
+		cfg.Categories = categories
+	}
+
+	e.int("MAX_PAGES", &cfg.MaxPages)
+	e.duration("WAIT_TIME_MIN", &cfg.WaitTimeMin)
+	e.duration("WAIT_TIME_MAX", &cfg.WaitTimeMax)
+	e.duration("PAGE_DELAY", &cfg.PageDelay)
+	e.bool("HEADLESS", &cfg.Headless)
+	e.string("USER_AGENT", &cfg.UserAgent)
+	e.string("BROWSER_ENGINE", &cfg.BrowserEngine)
+	e.duration("CLOUDFLARE_WAIT", &cfg.CloudflareWait)
+	e.int("RETRY_ATTEMPTS", &cfg.RetryAttempts)
+	e.duration("NAVIGATION_TIMEOUT", &cfg.NavigationTimeout)
+	e.duration("RUN_TIMEOUT", &cfg.RunTimeout)
+	e.bool("INCREMENTAL", &cfg.Incremental)
+	e.string("STATE_FILE", &cfg.StateFile)
+	e.bool("LIGHTWEIGHT_BROWSER", &cfg.LightweightBrowser)
+	e.bool("BLOCK_RESOURCES", &cfg.BlockResources)
+	e.duration("CONFIG_RELOAD_INTERVAL", &cfg.ReloadInterval)
+
+	e.secret("RABBITMQ_URL", &cfg.Queue.URL)
+	e.string("QUEUE_NAME", &cfg.Queue.Name)
+	e.string("RABBITMQ_HOST", &cfg.Queue.Host)
+	e.int("RABBITMQ_PORT", &cfg.Queue.Port)
+	e.string("RABBITMQ_USER", &cfg.Queue.User)
+	e.string("RABBITMQ_PASSWORD", &cfg.Queue.Password)
+	e.string("RABBITMQ_PASSWORD_FILE", &cfg.Queue.PasswordFile)
+	e.string("RABBITMQ_VHOST", &cfg.Queue.VHost)
+	e.bool("RABBITMQ_TLS", &cfg.Queue.TLS.Enabled)
+	e.string("RABBITMQ_CA_FILE", &cfg.Queue.TLS.CAFile)
+	e.string("RABBITMQ_CERT_FILE", &cfg.Queue.TLS.CertFile)
+	e.string("RABBITMQ_KEY_FILE", &cfg.Queue.TLS.KeyFile)
+	e.string("RABBITMQ_SERVER_NAME", &cfg.Queue.TLS.ServerName)
+	e.int("RABBITMQ_MAX_IN_FLIGHT", &cfg.Queue.MaxInFlight)
+
+	e.secret("DATABASE_URL", &cfg.Database.URL)
+	e.string("DATABASE_HOST", &cfg.Database.Host)
+	e.int("DATABASE_PORT", &cfg.Database.Port)
+	e.string("DATABASE_USER", &cfg.Database.User)
+	e.string("DATABASE_PASSWORD", &cfg.Database.Password)
+	e.string("DATABASE_PASSWORD_FILE", &cfg.Database.PasswordFile)
+	e.string("DATABASE_NAME", &cfg.Database.Name)
+	e.string("DATABASE_SSLMODE", &cfg.Database.SSLMode)
+	e.string("DATABASE_SSLROOTCERT", &cfg.Database.SSLRootCert)
+	e.string("DATABASE_SSLCERT", &cfg.Database.SSLCert)
+	e.string("DATABASE_SSLKEY", &cfg.Database.SSLKey)
+	e.int("SCRAPER_METRICS_PORT", &cfg.Metrics.ScraperPort)
+	e.int("CONSUMER_METRICS_PORT", &cfg.Metrics.ConsumerPort)
+
+	// Filters: <CATEGORY>_TARGETS="RTX 4060,RX 7600" and <CATEGORY>_FILTER="placa"
+	for i := range cfg.Categories {
+		cat := &cfg.Categories[i]
+		key := EnvKey(cat.Name)
+
+		if filter := getenv(key + "_FILTER"); filter != "" {
+			cat.Filter = strings.ToLower(filter)
+		}
+		e.list(key+"_TARGETS", &cat.Targets)
+		e.list(key+"_CONDITIONS", &cat.Conditions)
+		e.bool(key+"_EXCLUDE_BUNDLES", &cat.ExcludeBundles)
+		e.int(key+"_MAX_PAGES", &cat.MaxPages)
+		e.duration(key+"_TIME_BUDGET", &cat.TimeBudget)
+	}
+
+	searchTargets := false
+	if e.bool("SEARCH_TARGETS", &searchTargets) {
+		for i := range cfg.Categories {
+			cfg.Categories[i].SearchTargets = searchTargets && len(cfg.Categories[i].Targets) > 0
+		}
+	}
+
+	return errors.Join(e.errs...)
+}
+
+// EnvKey turns a category name into an environment variable prefix,
+// e.g. "Placa Mãe" -> "PLACA_M_E".
+func EnvKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name))
+}
+
+// envReader parses variables into their destination, collecting errors
+// instead of stopping at the first one. Each method reports whether the
+// variable was set and valid.
+type envReader struct {
+	getenv func(string) string
+	errs   []error
+}
+
+func (e *envReader) string(key string, dst *string) bool {
+	value := e.getenv(key)
+	if value == "" {
+		return false
+	}
+	*dst = value
+	return true
+}
+
+// secret reads key, or the file named by key_FILE, which wins when both are
+// set.
+func (e *envReader) secret(key string, dst *string) bool {
+	path := e.getenv(key + "_FILE")
+	if path == "" {
+		return e.string(key, dst)
+	}
+
+	value, err := readSecret(path)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s_FILE: %w", key, err))
+		return false
+	}
+	*dst = value
+	return true
+}
+
+func (e *envReader) list(key string, dst *[]string) bool {
+	value := e.getenv(key)
+	if value == "" {
+		return false
+	}
+	*dst = strings.Split(value, ",")
+	return true
+}
+
+func (e *envReader) bool(key string, dst *bool) bool {
+	value := e.getenv(key)
+	if value == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: valor booleano inválido %q", key, value))
+		return false
+	}
+	*dst = b
+	return true
+}
+
+func (e *envReader) int(key string, dst *int) bool {
+	value := e.getenv(key)
+	if value == "" {
+		return false
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: número inteiro inválido %q", key, value))
+		return false
+	}
+	*dst = n
+	return true
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) bool {
+	value := e.getenv(key)
+	if value == "" {
+		return false
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: duração inválida %q (ex.: 5s, 2m)", key, value))
+		return false
+	}
+	*dst = d
+	return true
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration with the following precedence, lowest
+// first: NewDefault, the YAML or TOML file at path (skipped when empty), the
+// categories file named by CATEGORIES_FILE, then the remaining environment
+// variables. Password files are read last, and the result is validated
+// before it is returned.
+func Load(path string) (*Config, error) {
+	var data []byte
+	if path != "" {
+		var err error
+		if data, err = readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return build(path, data)
+}
+
+// build runs the Load pipeline on file contents that were already read.
+func build(path string, data []byte) (*Config, error) {
+	cfg := NewDefault()
+
+	if path != "" {
+		if err := decode(cfg, path, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ApplyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.loadSecrets(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadFile overlays the file at path onto cfg. The format follows the
+// extension (.yaml, .yml or .toml). Keys left out keep their current value,
+// lists given in the file replace the current ones and unknown keys are an
+// error.
+func LoadFile(cfg *Config, path string) error {
+	data, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	return decode(cfg, path, data)
+}
+
+func readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de configuração: %w", err)
+	}
+	return data, nil
+}
+
+func decode(cfg *Config, path string, data []byte) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("erro ao decodificar %s: %w", path, err)
+		}
+
+	case ".toml":
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("erro ao decodificar %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return fmt.Errorf("erro ao decodificar %s: campos desconhecidos: %s", path, strings.Join(keys, ", "))
+		}
+
+	default:
+		return fmt.Errorf("formato de configuração não suportado %q (use .yaml, .yml ou .toml)", ext)
+	}
+
+	return nil
+}
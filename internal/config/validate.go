@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+var (
+	validEngines    = []string{"chromium", "firefox", "webkit"}
+	validBehaviors  = []string{"none", "light", "realistic"}
+	validKinds      = []string{"gpu", "cpu", "motherboard", "ram", "ssd", "psu", "case"}
+	validConditions = []string{"new", "open_box", "refurbished"}
+	validSSLModes   = []string{"disable", "require", "verify-ca", "verify-full"}
+)
+
+// Validate reports every invalid setting at once, each error naming the
+// offending field the way it is written in the configuration file.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.check(c.MaxPages >= 1, "max_pages deve ser ao menos 1 (atual: %d)", c.MaxPages)
+	v.check(c.WaitTimeMin >= 0, "wait_time_min não pode ser negativo (atual: %s)", c.WaitTimeMin)
+	v.check(c.WaitTimeMin <= c.WaitTimeMax,
+		"wait_time_min (%s) maior que wait_time_max (%s)", c.WaitTimeMin, c.WaitTimeMax)
+	v.check(c.PageDelay >= 0, "page_delay não pode ser negativo (atual: %s)", c.PageDelay)
+	v.check(c.CloudflareWait >= 0, "cloudflare_wait não pode ser negativo (atual: %s)", c.CloudflareWait)
+	v.check(c.NavigationTimeout > 0, "navigation_timeout deve ser positivo (atual: %s)", c.NavigationTimeout)
+	v.check(c.RunTimeout >= 0, "run_timeout não pode ser negativo (atual: %s)", c.RunTimeout)
+	v.check(c.RetryAttempts >= 0, "retry_attempts não pode ser negativo (atual: %d)", c.RetryAttempts)
+	v.check(c.PageRecycleEvery >= 0, "page_recycle_every não pode ser negativo (atual: %d)", c.PageRecycleEvery)
+	v.check(c.ReloadInterval >= 0, "reload_interval não pode ser negativo (atual: %s)", c.ReloadInterval)
+	v.oneOf("browser_engine", strings.ToLower(c.BrowserEngine), validEngines)
+
+	if c.Incremental {
+		v.check(c.IncrementalStopAfter >= 1,
+			"incremental_stop_after deve ser ao menos 1 (atual: %d)", c.IncrementalStopAfter)
+		v.check(c.FullRunInterval > 0, "full_run_interval deve ser positivo (atual: %s)", c.FullRunInterval)
+		v.check(c.StateFile != "", "state_file é obrigatório com incremental ativado")
+	}
+
+	for i, f := range c.Fingerprints {
+		field := fmt.Sprintf("fingerprints[%d]", i)
+		v.oneOf(field+".engine", strings.ToLower(f.Engine), validEngines)
+		v.check(f.UserAgent != "", "%s.user_agent é obrigatório", field)
+		v.check(f.ViewportWidth > 0 && f.ViewportHeight > 0,
+			"%s: viewport inválido %dx%d", field, f.ViewportWidth, f.ViewportHeight)
+	}
+
+	stores := make(map[string]StoreConfig)
+	for i, s := range c.Stores {
+		field := fmt.Sprintf("stores[%d]", i)
+		v.check(s.Name != "", "%s.name é obrigatório", field)
+		_, dup := stores[s.Name]
+		v.check(!dup, "%s: loja %q duplicada", field, s.Name)
+		stores[s.Name] = s
+
+		if s.Behavior != "" {
+			v.oneOf(field+".behavior", strings.ToLower(s.Behavior), validBehaviors)
+		}
+		if s.SearchURL != "" {
+			v.url(field+".search_url", s.SearchURL)
+			v.check(strings.Contains(s.SearchURL, "{query}"),
+				"%s.search_url deve conter {query} (atual: %q)", field, s.SearchURL)
+		}
+	}
+
+	v.check(len(c.Categories) > 0, "categories: nenhuma categoria configurada")
+	names := make(map[string]bool)
+	for i, cat := range c.Categories {
+		field := fmt.Sprintf("categories[%d]", i)
+		if cat.Name != "" {
+			field = fmt.Sprintf("categories[%s]", cat.Name)
+		}
+
+		v.check(cat.Name != "", "%s.name é obrigatório", field)
+		v.check(!names[strings.ToUpper(cat.Name)], "%s: categoria duplicada", field)
+		names[strings.ToUpper(cat.Name)] = true
+
+		v.url(field+".url", cat.URL)
+		// Titles are lowercased before the filter is matched.
+		v.check(cat.Filter == strings.ToLower(cat.Filter),
+			"%s.filter deve estar em minúsculas (atual: %q, use %q)", field, cat.Filter, strings.ToLower(cat.Filter))
+		if cat.Kind != "" {
+			v.oneOf(field+".kind", strings.ToLower(cat.Kind), validKinds)
+		}
+		for _, cond := range cat.Conditions {
+			v.oneOf(field+".conditions", strings.TrimSpace(cond), validConditions)
+		}
+		v.check(cat.PageSize >= 0, "%s.page_size não pode ser negativo (atual: %d)", field, cat.PageSize)
+		if cat.SearchTargets {
+			v.check(len(cat.Targets) > 0, "%s: search_targets exige targets", field)
+		}
+
+		v.check(cat.MaxPages >= 0 && cat.WaitTimeMin >= 0 && cat.WaitTimeMax >= 0 &&
+			cat.NavigationTimeout >= 0 && cat.TimeBudget >= 0,
+			"%s: max_pages, wait_time_min, wait_time_max, navigation_timeout e time_budget não podem ser negativos", field)
+		if limits := c.Limits(cat); cat.WaitTimeMin > 0 || cat.WaitTimeMax > 0 {
+			v.check(limits.WaitTimeMin <= limits.WaitTimeMax,
+				"%s: wait_time_min (%s) maior que wait_time_max (%s)", field, limits.WaitTimeMin, limits.WaitTimeMax)
+		}
+		if c.RunTimeout > 0 {
+			v.check(cat.TimeBudget <= c.RunTimeout,
+				"%s.time_budget (%s) maior que run_timeout (%s)", field, cat.TimeBudget, c.RunTimeout)
+		}
+	}
+
+	v.check(c.Queue.Name != "", "queue.name é obrigatório")
+	if c.Queue.URL != "" {
+		v.url("queue.url", c.Queue.URL, "amqp", "amqps")
+	} else {
+		v.check(c.Queue.Host != "", "queue.host é obrigatório sem queue.url")
+		v.check(c.Queue.Port >= 0 && c.Queue.Port <= 65535, "queue.port fora do intervalo (atual: %d)", c.Queue.Port)
+	}
+	v.check(c.Queue.MaxInFlight >= 1, "queue.max_in_flight deve ser >= 1 (atual: %d)", c.Queue.MaxInFlight)
+	if c.Queue.TLS.Enabled {
+		v.check(c.Queue.URL == "" || strings.HasPrefix(c.Queue.URL, "amqps://"),
+			"queue.url deve usar amqps:// com queue.tls.enabled")
+		v.files("queue.tls", c.Queue.TLS.CAFile, c.Queue.TLS.CertFile, c.Queue.TLS.KeyFile)
+	}
+
+	if c.Database.URL != "" {
+		v.url("database.url", c.Database.URL, "postgres", "postgresql")
+	} else {
+		v.check(c.Database.Host != "", "database.host é obrigatório sem database.url")
+		v.check(c.Database.Name != "", "database.name é obrigatório sem database.url")
+		v.check(c.Database.Port >= 0 && c.Database.Port <= 65535, "database.port fora do intervalo (atual: %d)", c.Database.Port)
+		v.oneOf("database.sslmode", c.Database.SSLMode, validSSLModes)
+		v.files("database", c.Database.SSLRootCert, c.Database.SSLCert, c.Database.SSLKey)
+	}
+	v.port("metrics.scraper_port", c.Metrics.ScraperPort)
+	v.port("metrics.consumer_port", c.Metrics.ConsumerPort)
+
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf(format, args...))
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.errs = append(v.errs, fmt.Errorf("%s: valor %q inválido (use %s)", field, value, strings.Join(allowed, ", ")))
+}
+
+// url checks that raw is an absolute URL; schemes defaults to http and https.
+func (v *validator) url(field, raw string, schemes ...string) {
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		// The unwrapped error leaves the raw URL, and any password, out.
+		v.errs = append(v.errs, fmt.Errorf("%s: URL inválida: %w", field, errors.Unwrap(err)))
+		return
+	}
+	if u.Host == "" {
+		v.errs = append(v.errs, fmt.Errorf("%s: URL inválida %q", field, u.Redacted()))
+		return
+	}
+	v.oneOf(field+" (esquema)", u.Scheme, schemes)
+}
+
+// files checks that the CA, certificate and key files exist, and that the
+// certificate and key come together.
+func (v *validator) files(field, ca, cert, key string) {
+	for _, path := range []string{ca, cert, key} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			v.errs = append(v.errs, fmt.Errorf("%s: arquivo inacessível %q", field, path))
+		}
+	}
+	v.check((cert == "") == (key == ""), "%s: certificado e chave do cliente devem ser informados juntos", field)
+}
+
+func (v *validator) port(field string, port int) {
+	v.check(port > 0 && port <= 65535, "%s fora do intervalo 1-65535 (atual: %d)", field, port)
+}
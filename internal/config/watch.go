@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// Watcher polls a configuration file and keeps the last valid Config built
+// from it. A change that fails to load or validate is rejected and the
+// previous Config stays active.
+type Watcher struct {
+	path     string
+	current  atomic.Pointer[Config]
+	version  atomic.Int64
+	checksum string
+	seen     string
+	lastErr  string
+
+	// OnReload is called after a new Config becomes active, OnReject when a
+	// change is rejected. Both run on the goroutine calling Run.
+	OnReload func(cfg *Config, version int64, checksum string)
+	OnReject func(err error)
+}
+
+// NewWatcher starts at version 1 with initial, which should have been loaded
+// from path.
+func NewWatcher(path string, initial *Config) *Watcher {
+	w := &Watcher{path: path}
+	if data, err := readFile(path); err == nil {
+		w.checksum = checksum(data)
+		w.seen = w.checksum
+	}
+	w.current.Store(initial)
+	w.version.Store(1)
+	return w
+}
+
+// Current returns the active Config. It is safe for concurrent use; callers
+// must treat the returned value as read-only.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+func (w *Watcher) Version() int64 {
+	return w.version.Load()
+}
+
+// Checksum is a short hash of the file contents behind the active Config.
+// Unlike Current it must not be called concurrently with Run.
+func (w *Watcher) Checksum() string {
+	return w.checksum
+}
+
+// Run checks the file every interval until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Check reloads the file if its contents changed since the last check.
+func (w *Watcher) Check() {
+	data, err := readFile(w.path)
+	if err != nil {
+		// Editors often replace files in two steps; report each distinct
+		// error once instead of on every poll.
+		if err.Error() != w.lastErr {
+			w.lastErr = err.Error()
+			w.reject(err)
+		}
+		return
+	}
+	w.lastErr = ""
+
+	sum := checksum(data)
+	if sum == w.seen {
+		return
+	}
+	w.seen = sum
+
+	cfg, err := build(w.path, data)
+	if err != nil {
+		w.reject(err)
+		return
+	}
+
+	w.checksum = sum
+	w.current.Store(cfg)
+	version := w.version.Add(1)
+	if w.OnReload != nil {
+		w.OnReload(cfg, version, sum)
+	}
+}
+
+func (w *Watcher) reject(err error) {
+	if w.OnReject != nil {
+		w.OnReject(err)
+	}
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
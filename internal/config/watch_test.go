@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestWatcherAppliesValidChanges(t *testing.T) {
+	path := writeFile(t, "config.yaml", "max_pages: 3\n")
+	initial, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher(path, initial)
+	var reloads, rejects int
+	w.OnReload = func(*Config, int64, string) { reloads++ }
+	w.OnReject = func(error) { rejects++ }
+
+	w.Check()
+	if reloads != 0 || w.Current() != initial {
+		t.Fatalf("unchanged file reloaded (reloads=%d)", reloads)
+	}
+
+	if err := os.WriteFile(path, []byte("max_pages: 8\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.Check()
+	if reloads != 1 || w.Current().MaxPages != 8 || w.Version() != 2 {
+		t.Errorf("reloads=%d MaxPages=%d version=%d, want 1, 8, 2",
+			reloads, w.Current().MaxPages, w.Version())
+	}
+	if rejects != 0 {
+		t.Errorf("rejects = %d, want 0", rejects)
+	}
+}
+
+func TestWatcherKeepsConfigOnInvalidChange(t *testing.T) {
+	path := writeFile(t, "config.yaml", "max_pages: 3\n")
+	initial, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher(path, initial)
+	checksum := w.Checksum()
+	var rejected []error
+	w.OnReject = func(err error) { rejected = append(rejected, err) }
+
+	if err := os.WriteFile(path, []byte("wait_time_min: 1m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.Check()
+	w.Check()
+
+	if len(rejected) != 1 {
+		t.Fatalf("rejections = %d, want 1 per change", len(rejected))
+	}
+	if w.Current() != initial || w.Version() != 1 || w.Checksum() != checksum {
+		t.Errorf("invalid change replaced the active config")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	w.Check()
+	if len(rejected) != 2 || !errors.Is(rejected[1], os.ErrNotExist) {
+		t.Errorf("missing file not reported: %v", rejected)
+	}
+}
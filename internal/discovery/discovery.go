@@ -0,0 +1,196 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+// Category is a listing page found in the store menu or sitemap.
+type Category struct {
+	Name     string
+	URL      string
+	Source   string
+	Products int
+}
+
+// menuScript returns every link in the page navigation as {text, href}.
+const menuScript = `() => JSON.stringify(Array.from(
+	document.querySelectorAll("nav a[href], header a[href], [role=menu] a[href]"),
+	(a) => ({ text: (a.textContent || "").trim(), href: a.href })
+))`
+
+var productCountPattern = regexp.MustCompile(`(?i)([\d.]+)\s+(?:produtos|resultados|itens)`)
+
+// CategoriesFromURLs keeps the URLs that are direct children of pathPrefix
+// (e.g. /hardware/placa-de-video for "/hardware/"), deduplicated.
+func CategoriesFromURLs(urls []string, pathPrefix, source string) []Category {
+	seen := make(map[string]bool)
+	var categories []Category
+
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+
+		slug, ok := categorySlug(u.Path, pathPrefix)
+		if !ok {
+			continue
+		}
+
+		canonical := canonicalURL(u)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		categories = append(categories, Category{
+			Name:   slug,
+			URL:    canonical,
+			Source: source,
+		})
+	}
+
+	return categories
+}
+
+// canonicalURL drops the query, fragment and trailing slash, so links such
+// as ".../placa-de-video/?utm_source=menu#top" compare equal.
+func canonicalURL(u *url.URL) string {
+	return u.Scheme + "://" + strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/")
+}
+
+// normalizeURL is canonicalURL for a raw URL; unparseable ones are returned
+// trimmed of a trailing slash.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return strings.TrimSuffix(raw, "/")
+	}
+	return canonicalURL(u)
+}
+
+func categorySlug(path, prefix string) (string, bool) {
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	slug := strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	if slug == "" || strings.Contains(slug, "/") {
+		return "", false
+	}
+	return slug, true
+}
+
+// MenuCategories reads the navigation links of the page currently loaded.
+func MenuCategories(page playwright.Page, pathPrefix string) ([]Category, error) {
+	result, err := page.Evaluate(menuScript)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler menu: %w", err)
+	}
+
+	raw, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("resultado inesperado do menu: %T", result)
+	}
+
+	var links []struct {
+		Text string `json:"text"`
+		Href string `json:"href"`
+	}
+	if err := json.Unmarshal([]byte(raw), &links); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar menu: %w", err)
+	}
+
+	names := make(map[string]string)
+	hrefs := make([]string, 0, len(links))
+	for _, link := range links {
+		hrefs = append(hrefs, link.Href)
+		if link.Text != "" {
+			names[normalizeURL(link.Href)] = link.Text
+		}
+	}
+
+	categories := CategoriesFromURLs(hrefs, pathPrefix, "menu")
+	for i, c := range categories {
+		if name, ok := names[c.URL]; ok {
+			categories[i].Name = name
+		}
+	}
+
+	return categories, nil
+}
+
+// ProductCount extracts the "N produtos" total shown on a listing page.
+func ProductCount(text string) (int, bool) {
+	match := productCountPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(match[1], ".", ""))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// Merge combines menu and sitemap results, preferring menu names, and
+// returns them sorted by URL.
+func Merge(lists ...[]Category) []Category {
+	byURL := make(map[string]Category)
+	for _, list := range lists {
+		for _, c := range list {
+			existing, ok := byURL[c.URL]
+			if !ok {
+				byURL[c.URL] = c
+				continue
+			}
+			if existing.Source != "menu" && c.Source == "menu" {
+				existing.Name = c.Name
+				existing.Source = c.Source
+			}
+			if c.Products > existing.Products {
+				existing.Products = c.Products
+			}
+			byURL[c.URL] = existing
+		}
+	}
+
+	merged := make([]Category, 0, len(byURL))
+	for _, c := range byURL {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].URL < merged[j].URL })
+	return merged
+}
+
+// AddToConfig appends discovered categories whose URL is not configured yet,
+// leaving existing entries (and their filters and targets) untouched.
+func AddToConfig(existing []config.CategoryConfig, discovered []Category) ([]config.CategoryConfig, int) {
+	known := make(map[string]bool, len(existing))
+	for _, c := range existing {
+		known[normalizeURL(c.URL)] = true
+	}
+
+	result := append([]config.CategoryConfig(nil), existing...)
+	added := 0
+	for _, c := range discovered {
+		if known[c.URL] {
+			continue
+		}
+		result = append(result, config.CategoryConfig{
+			Name: c.Name,
+			URL:  c.URL,
+		})
+		added++
+	}
+
+	return result, added
+}
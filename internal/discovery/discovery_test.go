@@ -0,0 +1,129 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+func TestFetchSitemapFollowsIndex(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s/categorias.xml</loc></sitemap></sitemapindex>`, server.URL)
+		case "/categorias.xml":
+			fmt.Fprint(w, `<urlset>
+				<url><loc> https://www.pichau.com.br/hardware/placa-de-video </loc></url>
+				<url><loc>https://www.pichau.com.br/hardware/ssd/</loc></url>
+				<url><loc>https://www.pichau.com.br/hardware/ssd/ssd-nvme</loc></url>
+				<url><loc>https://www.pichau.com.br/perifericos/mouse</loc></url>
+			</urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	urls, err := FetchSitemap(context.Background(), server.Client(), server.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("FetchSitemap() erro: %v", err)
+	}
+	if len(urls) != 4 {
+		t.Fatalf("len(urls) = %d, want 4", len(urls))
+	}
+
+	categories := CategoriesFromURLs(urls, "/hardware/", "sitemap")
+	if len(categories) != 2 {
+		t.Fatalf("len(categories) = %d, want 2: %+v", len(categories), categories)
+	}
+	if categories[1].Name != "ssd" || categories[1].URL != "https://www.pichau.com.br/hardware/ssd" {
+		t.Errorf("categoria inesperada: %+v", categories[1])
+	}
+}
+
+func TestProductCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+		ok   bool
+	}{
+		{"Placas de Vídeo\n1.234 produtos encontrados", 1234, true},
+		{"48 resultados", 48, true},
+		{"Nenhum item", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ProductCount(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ProductCount(%q) = (%d, %v), want (%d, %v)", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAddToConfigKeepsExistingEntries(t *testing.T) {
+	existing := []config.CategoryConfig{
+		{Name: "GPU", URL: "https://www.pichau.com.br/hardware/placa-de-video/", Filter: "placa"},
+	}
+	discovered := Merge(
+		[]Category{{Name: "Placas de Vídeo", URL: "https://www.pichau.com.br/hardware/placa-de-video", Source: "menu"}},
+		[]Category{{Name: "ssd", URL: "https://www.pichau.com.br/hardware/ssd", Source: "sitemap"}},
+	)
+
+	merged, added := AddToConfig(existing, discovered)
+	if added != 1 || len(merged) != 2 {
+		t.Fatalf("AddToConfig() added=%d len=%d, want 1 e 2", added, len(merged))
+	}
+	if merged[0].Filter != "placa" {
+		t.Errorf("entrada existente foi alterada: %+v", merged[0])
+	}
+}
+
+type menuPage struct {
+	playwright.Page
+	links string
+}
+
+func (p *menuPage) Evaluate(string, ...interface{}) (interface{}, error) { return p.links, nil }
+
+func TestMenuCategoriesNormalizesLinks(t *testing.T) {
+	page := &menuPage{links: `[
+		{"text": "Placas de Vídeo", "href": "https://www.pichau.com.br/hardware/placa-de-video/?utm_source=menu#top"},
+		{"text": "", "href": "https://www.pichau.com.br/hardware/placa-de-video"},
+		{"text": "SSD", "href": "https://WWW.pichau.com.br/hardware/ssd#destaques"}
+	]`}
+
+	categories, err := MenuCategories(page, "/hardware/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Category{
+		{Name: "Placas de Vídeo", URL: "https://www.pichau.com.br/hardware/placa-de-video", Source: "menu"},
+		{Name: "SSD", URL: "https://www.pichau.com.br/hardware/ssd", Source: "menu"},
+	}
+	if len(categories) != len(want) {
+		t.Fatalf("MenuCategories() = %+v, want %+v", categories, want)
+	}
+	for i := range want {
+		if categories[i] != want[i] {
+			t.Errorf("categoria %d = %+v, want %+v", i, categories[i], want[i])
+		}
+	}
+}
+
+func TestAddToConfigMatchesURLsWithQuery(t *testing.T) {
+	existing := []config.CategoryConfig{
+		{Name: "GPU", URL: "https://www.pichau.com.br/hardware/placa-de-video?sort=price_asc"},
+	}
+	discovered := []Category{{Name: "placa-de-video", URL: "https://www.pichau.com.br/hardware/placa-de-video"}}
+
+	if _, added := AddToConfig(existing, discovered); added != 0 {
+		t.Errorf("AddToConfig() added=%d, want 0", added)
+	}
+}
@@ -0,0 +1,85 @@
+package discovery
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const maxSitemapDepth = 3
+
+type sitemapDocument struct {
+	XMLName  xml.Name
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+}
+
+// FetchSitemap returns every page URL listed in a sitemap, following nested
+// sitemap indexes up to a fixed depth.
+func FetchSitemap(ctx context.Context, client *http.Client, sitemapURL string) ([]string, error) {
+	return fetchSitemap(ctx, client, sitemapURL, 0)
+}
+
+func fetchSitemap(ctx context.Context, client *http.Client, sitemapURL string, depth int) ([]string, error) {
+	if depth > maxSitemapDepth {
+		return nil, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar sitemap %s: %w", sitemapURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sitemap %s retornou status %d", sitemapURL, resp.StatusCode)
+	}
+
+	var reader io.Reader = resp.Body
+	if strings.HasSuffix(sitemapURL, ".gz") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao descompactar sitemap %s: %w", sitemapURL, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler sitemap %s: %w", sitemapURL, err)
+	}
+
+	var doc sitemapDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar sitemap %s: %w", sitemapURL, err)
+	}
+
+	var urls []string
+	for _, u := range doc.URLs {
+		urls = append(urls, strings.TrimSpace(u.Loc))
+	}
+
+	for _, child := range doc.Sitemaps {
+		childURLs, err := fetchSitemap(ctx, client, strings.TrimSpace(child.Loc), depth+1)
+		if err != nil {
+			return urls, err
+		}
+		urls = append(urls, childURLs...)
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const CurrencyBRL = "BRL"
+
+// Money is an amount in hundredths of the currency unit (centavos for BRL).
+// Prices are kept as integers end to end, so they compare exactly and
+// round-trip through JSON, CSV and DECIMAL(10,2) without rounding drift.
+// The zero value means "no price".
+type Money struct {
+	Cents    int64  `json:"cents"`
+	Currency string `json:"currency,omitempty"`
+}
+
+func BRL(cents int64) Money {
+	return Money{Cents: cents, Currency: CurrencyBRL}
+}
+
+// MoneyFromFloat rounds amount to the nearest cent. It is only meant for
+// values that were floats to begin with, such as legacy messages.
+func MoneyFromFloat(amount float64, currency string) Money {
+	cents := int64(math.Round(amount * 100))
+	if cents == 0 {
+		return Money{}
+	}
+	return Money{Cents: cents, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount with a dot separator and at most two
+// fractional digits, such as "1899.90", "1899.9" or "1899", as written by
+// String. An empty string is the zero Money.
+func ParseMoney(s, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, nil
+	}
+
+	digits := strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || len(frac) > 2 || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("valor monetário inválido %q", s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("valor monetário inválido %q", s)
+	}
+	if digits != s {
+		cents = -cents
+	}
+	if cents == 0 {
+		return Money{}, nil
+	}
+	return Money{Cents: cents, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) IsZero() bool {
+	return m.Cents == 0
+}
+
+// Float64 converts to a float for ratios and display; never store it.
+func (m Money) Float64() float64 {
+	return float64(m.Cents) / 100
+}
+
+// String formats the amount as a plain decimal, "1899.90", without the
+// currency.
+func (m Money) String() string {
+	sign, cents := "", m.Cents
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// UnmarshalJSON also accepts a bare number, the float prices of messages
+// and state files written before Money existed, read as BRL.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var amount float64
+	if err := json.Unmarshal(data, &amount); err == nil {
+		*m = MoneyFromFloat(amount, CurrencyBRL)
+		return nil
+	}
+
+	type plain Money
+	var v plain
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*m = Money(v)
+	return nil
+}
+
+// Value writes the amount as a decimal string, which PostgreSQL converts to
+// NUMERIC exactly. The currency is stored in its own column.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads a NUMERIC column as BRL; NULL is the zero Money. Callers that
+// select the currency column set it afterwards.
+func (m *Money) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+	case []byte:
+		*m, err = ParseMoney(string(v), CurrencyBRL)
+	case string:
+		*m, err = ParseMoney(v, CurrencyBRL)
+	case int64:
+		*m = Money{Cents: v * 100, Currency: CurrencyBRL}
+	case float64:
+		*m = MoneyFromFloat(v, CurrencyBRL)
+	default:
+		err = fmt.Errorf("tipo incompatível com Money: %T", src)
+	}
+	return err
+}
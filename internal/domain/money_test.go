@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyStringRoundTrip(t *testing.T) {
+	for _, cents := range []int64{1, 10, 99, 100, 189990, 199999, 100000000, -550} {
+		m := BRL(cents)
+		got, err := ParseMoney(m.String(), CurrencyBRL)
+		if err != nil {
+			t.Fatalf("ParseMoney(%q): %v", m.String(), err)
+		}
+		if got != m {
+			t.Errorf("ParseMoney(%q) = %+v, want %+v", m.String(), got, m)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Money
+	}{
+		{"1899.90", BRL(189990)},
+		{"1899.9", BRL(189990)},
+		{"1899", BRL(189900)},
+		{"0.01", BRL(1)},
+		{"", Money{}},
+		{"0.00", Money{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input, CurrencyBRL)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %+v, %v, want %+v", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, input := range []string{"1.899,90", "1899.999", "R$ 10", "abc", ".50", "1e3"} {
+		if _, err := ParseMoney(input, CurrencyBRL); err == nil {
+			t.Errorf("ParseMoney(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	for _, m := range []Money{BRL(30), BRL(189990), BRL(1999999), {}} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Money
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != m {
+			t.Errorf("JSON %s = %+v, want %+v", data, got, m)
+		}
+	}
+}
+
+func TestMoneyUnmarshalLegacyFloat(t *testing.T) {
+	var got Money
+	if err := json.Unmarshal([]byte("1899.9"), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != BRL(189990) {
+		t.Errorf("got %+v, want %+v", got, BRL(189990))
+	}
+}
+
+func TestMoneySQLRoundTrip(t *testing.T) {
+	for _, m := range []Money{BRL(1), BRL(189990), BRL(99999999)} {
+		v, err := m.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Money
+		// lib/pq returns NUMERIC columns as []byte.
+		if err := got.Scan([]byte(v.(string))); err != nil {
+			t.Fatal(err)
+		}
+		if got != m {
+			t.Errorf("Scan(Value(%+v)) = %+v", m, got)
+		}
+	}
+
+	var null Money
+	if err := null.Scan(nil); err != nil || !null.IsZero() {
+		t.Errorf("Scan(nil) = %+v, %v, want zero", null, err)
+	}
+}
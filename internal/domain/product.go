@@ -1,12 +1,47 @@
 package domain
 
+import "time"
+
+const (
+	ConditionNew         = "new"
+	ConditionOpenBox     = "open_box"
+	ConditionRefurbished = "refurbished"
+)
+
 type Product struct {
 	Title    string
 	Brand    string
-	Price    float64
+	Price    Money
 	RawPrice string
 	Page     int
 	Category string
+	// Attributes holds category-specific specs parsed from the title, such
+	// as socket, capacity or wattage.
+	Attributes map[string]string
+
+	// Promotion data shown on the card: the struck-through list price, the
+	// advertised "% OFF" and any coupon code with its condition text.
+	OriginalPrice      Money
+	AdvertisedDiscount float64
+	CouponCode         string
+	CouponCondition    string
+
+	// Condition is one of the Condition* constants; Bundle marks kits and
+	// items sold together with extras such as a cooler.
+	Condition string
+	Bundle    bool
+
+	// Rating is the average star rating (0-5) shown on the card and
+	// ReviewCount the number of reviews behind it.
+	Rating      float64
+	ReviewCount int
+
+	// ScrapedAt is when the card was read. It travels in the queue envelope
+	// rather than in the product payload.
+	ScrapedAt time.Time `json:"-"`
+	// MessageID is the ID of the queue message that delivered the product,
+	// used to drop redeliveries. It is empty for legacy messages.
+	MessageID string `json:"-"`
 }
 
 func (p Product) UniqueKey() string {
@@ -3,11 +3,13 @@ package export
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vitor-labes/pc-scraper/internal/domain"
@@ -35,13 +37,31 @@ func ToCSV(products []domain.Product) error {
 	}
 	defer file.Close()
 
-	file.WriteString("\uFEFF")
+	if err := writeCSV(file, products); err != nil {
+		return err
+	}
+
+	slog.Info("CSV exportado com sucesso",
+		"filepath", filepath,
+		"total_products", len(products),
+	)
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	return nil
+}
+
+// writeCSV writes the products sorted by category and price, preceded by a
+// BOM so spreadsheets detect UTF-8.
+func writeCSV(w io.Writer, products []domain.Product) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return fmt.Errorf("erro ao escrever arquivo: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
 
 	if err := writer.Write([]string{
-		"Categoria", "Marca", "Título", "Preço", "Preço Raw", "Página",
+		"Categoria", "Marca", "Título", "Preço", "Moeda", "Preço Raw", "Página", "Atributos",
+		"Preço Original", "Desconto Anunciado (%)", "Cupom", "Condição", "Kit",
+		"Avaliação", "Nº Avaliações", "Coletado Em",
 	}); err != nil {
 		return fmt.Errorf("erro ao escrever cabeçalho: %w", err)
 	}
@@ -53,7 +73,7 @@ func ToCSV(products []domain.Product) error {
 		if sortedProducts[i].Category != sortedProducts[j].Category {
 			return sortedProducts[i].Category < sortedProducts[j].Category
 		}
-		return sortedProducts[i].Price < sortedProducts[j].Price
+		return sortedProducts[i].Price.Cents < sortedProducts[j].Price.Cents
 	})
 
 	for _, p := range sortedProducts {
@@ -61,9 +81,19 @@ func ToCSV(products []domain.Product) error {
 			p.Category,
 			p.Brand,
 			p.Title,
-			fmt.Sprintf("%.2f", p.Price),
+			p.Price.String(),
+			p.Price.Currency,
 			p.RawPrice,
 			strconv.Itoa(p.Page),
+			formatAttributes(p.Attributes),
+			formatOptionalPrice(p.OriginalPrice),
+			formatOptionalFloat(p.AdvertisedDiscount),
+			p.CouponCode,
+			p.Condition,
+			strconv.FormatBool(p.Bundle),
+			formatOptionalFloat(p.Rating),
+			strconv.Itoa(p.ReviewCount),
+			formatOptionalTime(p.ScrapedAt),
 		}); err != nil {
 			slog.Error("erro ao escrever linha",
 				"product", p.Title,
@@ -73,14 +103,46 @@ func ToCSV(products []domain.Product) error {
 		}
 	}
 
+	writer.Flush()
 	if err := writer.Error(); err != nil {
 		return fmt.Errorf("erro ao finalizar escrita: %w", err)
 	}
 
-	slog.Info("CSV exportado com sucesso",
-		"filepath", filepath,
-		"total_products", len(products),
-	)
-
 	return nil
 }
+
+func formatAttributes(attributes map[string]string) string {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+attributes[k])
+	}
+	return strings.Join(parts, "; ")
+}
+
+func formatOptionalPrice(v domain.Money) string {
+	if v.IsZero() {
+		return ""
+	}
+	return v.String()
+}
+
+// formatOptionalTime writes timestamps in UTC, to the second.
+func formatOptionalTime(v time.Time) string {
+	if v.IsZero() {
+		return ""
+	}
+	return v.UTC().Format(time.RFC3339)
+}
+
+func formatOptionalFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	products := []domain.Product{
+		{
+			Category:           "GPU",
+			Brand:              "ASUS",
+			Title:              "Placa de Video ASUS RTX 4060, 8GB",
+			Price:              domain.BRL(189990),
+			RawPrice:           "R$ 1.899,90",
+			Page:               2,
+			Attributes:         map[string]string{"chipset": "RTX 4060", "memory": "8GB"},
+			OriginalPrice:      domain.BRL(219990),
+			AdvertisedDiscount: 13.64,
+			CouponCode:         "PICHAU10",
+			Condition:          domain.ConditionNew,
+			Rating:             4.5,
+			ReviewCount:        12,
+			ScrapedAt:          time.Date(2024, 3, 15, 14, 30, 22, 0, time.UTC),
+		},
+		{
+			Category:      "GPU",
+			Title:         "Placa de Video RTX 4070 Importada",
+			Price:         domain.Money{Cents: 59999, Currency: "USD"},
+			OriginalPrice: domain.Money{Cents: 64999, Currency: "USD"},
+			Condition:     domain.ConditionNew,
+		},
+		{
+			Category:  "CPU",
+			Title:     "Processador Ryzen 5 5600 + Cooler",
+			Price:     domain.BRL(69900),
+			Condition: domain.ConditionOpenBox,
+			Bundle:    true,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, products); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// writeCSV sorts by category and price.
+	want := []domain.Product{products[2], products[1], products[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadCSVFromOlderVersion(t *testing.T) {
+	got, err := readCSV(strings.NewReader("Categoria,Título,Preço\nGPU,RTX 4060,1899.90\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !got[0].ScrapedAt.IsZero() || got[0].Price != domain.BRL(189990) {
+		t.Errorf("readCSV() = %+v, want one BRL product without ScrapedAt", got)
+	}
+}
+
+func TestReadCSVRequiresColumns(t *testing.T) {
+	_, err := readCSV(strings.NewReader("Categoria,Título\nGPU,RTX\n"))
+	if err == nil || !strings.Contains(err.Error(), "Preço") {
+		t.Errorf("readCSV() = %v, want missing Preço column", err)
+	}
+}
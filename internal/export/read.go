@@ -0,0 +1,162 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+// ReadCSV parses a file written by ToCSV back into products. Columns are
+// matched by header, so exports from older versions with fewer columns
+// still load; the coupon condition is not exported and comes back empty.
+// Products from files without "Coletado Em" have no ScrapedAt, and prices
+// from files without "Moeda" are read as BRL.
+func ReadCSV(path string) ([]domain.Product, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	defer file.Close()
+
+	return readCSV(file)
+}
+
+func readCSV(r io.Reader) ([]domain.Product, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimPrefix(name, "\uFEFF")] = i
+	}
+	for _, required := range []string{"Categoria", "Título", "Preço"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("coluna obrigatória ausente: %s", required)
+		}
+	}
+
+	var products []domain.Product
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+
+		p, err := parseRecord(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+		products = append(products, p)
+	}
+
+	return products, nil
+}
+
+func parseRecord(record []string, columns map[string]int) (domain.Product, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	p := domain.Product{
+		Category:   field("Categoria"),
+		Brand:      field("Marca"),
+		Title:      field("Título"),
+		RawPrice:   field("Preço Raw"),
+		Attributes: parseAttributes(field("Atributos")),
+		CouponCode: field("Cupom"),
+		Condition:  field("Condição"),
+	}
+
+	currency := field("Moeda")
+	if currency == "" {
+		currency = domain.CurrencyBRL
+	}
+
+	var err error
+	if p.Price, err = parseMoney("Preço", field("Preço"), currency); err != nil {
+		return p, err
+	}
+	if p.OriginalPrice, err = parseMoney("Preço Original", field("Preço Original"), currency); err != nil {
+		return p, err
+	}
+	if p.AdvertisedDiscount, err = parseFloat("Desconto Anunciado (%)", field("Desconto Anunciado (%)")); err != nil {
+		return p, err
+	}
+	if p.Rating, err = parseFloat("Avaliação", field("Avaliação")); err != nil {
+		return p, err
+	}
+	if v := field("Página"); v != "" {
+		if p.Page, err = strconv.Atoi(v); err != nil {
+			return p, fmt.Errorf("Página inválida %q", v)
+		}
+	}
+	if v := field("Nº Avaliações"); v != "" {
+		if p.ReviewCount, err = strconv.Atoi(v); err != nil {
+			return p, fmt.Errorf("Nº Avaliações inválido %q", v)
+		}
+	}
+	if v := field("Coletado Em"); v != "" {
+		if p.ScrapedAt, err = time.Parse(time.RFC3339, v); err != nil {
+			return p, fmt.Errorf("Coletado Em inválido %q", v)
+		}
+	}
+	if v := field("Kit"); v != "" {
+		if p.Bundle, err = strconv.ParseBool(v); err != nil {
+			return p, fmt.Errorf("Kit inválido %q", v)
+		}
+	}
+
+	return p, nil
+}
+
+// parseMoney reads prices as written by writeCSV.
+func parseMoney(column, v, currency string) (domain.Money, error) {
+	m, err := domain.ParseMoney(v, currency)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("%s inválido %q", column, v)
+	}
+	return m, nil
+}
+
+func parseFloat(column, v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválido %q", column, v)
+	}
+	return f, nil
+}
+
+// parseAttributes reverses formatAttributes.
+func parseAttributes(raw string) map[string]string {
+	if raw == "" {
+		return nil
+	}
+
+	attrs := make(map[string]string)
+	for _, part := range strings.Split(raw, "; ") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
@@ -50,6 +50,138 @@ var (
 		[]string{"category"},
 	)
 
+	ProductsSkipped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scraper_products_skipped_total",
+			Help: "Total number of product cards dropped during extraction, by reason",
+		},
+		[]string{"category", "reason"},
+	)
+
+	BlockedRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scraper_blocked_requests_total",
+			Help: "Total number of browser requests blocked, by resource type or tracker",
+		},
+		[]string{"type"},
+	)
+
+	BlockedBytesEstimated = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scraper_blocked_bytes_estimated_total",
+			Help: "Estimated bandwidth saved by blocked requests, in bytes",
+		},
+		[]string{"type"},
+	)
+
+	ExtractionFallbacks = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "scraper_extraction_fallbacks_total",
+			Help: "Total number of listing pages read with per-card locators after the bulk evaluation failed",
+		},
+	)
+
+	BrowserRestarts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scraper_browser_restarts_total",
+			Help: "Total number of browser relaunches and page recycles, by reason",
+		},
+		[]string{"reason"},
+	)
+
+	BehaviorDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "scraper_behavior_duration_seconds",
+			Help:    "Time spent simulating human behaviour on a page",
+			Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 20, 40},
+		},
+		[]string{"store", "profile"},
+	)
+
+	IncrementalStops = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scraper_incremental_stops_total",
+			Help: "Total number of listings cut short because pages were unchanged",
+		},
+		[]string{"category"},
+	)
+
+	CategoryBudgetExhausted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scraper_category_budget_exhausted_total",
+			Help: "Total number of categories stopped because their share of the run deadline ran out",
+		},
+		[]string{"category"},
+	)
+
+	ConfigVersion = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "scraper_config_version",
+			Help: "Version of the active configuration, incremented on every applied reload",
+		},
+	)
+
+	ConfigReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scraper_config_reloads_total",
+			Help: "Total number of configuration file reloads, by status (applied/rejected)",
+		},
+		[]string{"status"},
+	)
+
+	// Queue
+	QueueConnectionUp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "queue_connection_up",
+			Help: "1 while connected to RabbitMQ, 0 while reconnecting, by role (publisher/consumer)",
+		},
+		[]string{"role"},
+	)
+
+	QueueReconnects = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "queue_reconnects_total",
+			Help: "Total number of successful RabbitMQ reconnections, by role",
+		},
+		[]string{"role"},
+	)
+
+	// Publisher
+	MessagesPublished = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "publisher_messages_published_total",
+			Help: "Total number of messages sent to the broker",
+		},
+	)
+
+	MessagesConfirmed = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "publisher_messages_confirmed_total",
+			Help: "Total number of published messages confirmed by the broker",
+		},
+	)
+
+	MessagesNacked = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "publisher_messages_nacked_total",
+			Help: "Total number of published messages rejected by the broker",
+		},
+	)
+
+	MessagesReturned = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "publisher_messages_returned_total",
+			Help: "Total number of published messages returned as unroutable",
+		},
+	)
+
+	MessagesInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "publisher_messages_in_flight",
+			Help: "Published messages awaiting broker confirmation",
+		},
+	)
+
 	// Consumer
 	MessagesProcessed = promauto.NewCounterVec(
 		prometheus.CounterOpts{
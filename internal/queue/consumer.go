@@ -2,74 +2,99 @@ package queue
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
 	"github.com/vitor-labes/pc-scraper/internal/domain"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
 )
 
 type MessageHandler func(context.Context, domain.Product) error
 
+// errConnectionLost ends one consuming session; Start then reconnects.
+var errConnectionLost = errors.New("conexão com RabbitMQ perdida")
+
+// Consumer delivers queued products to a handler. When the connection
+// drops it reconnects with backoff, redeclares the queue and resumes; the
+// broker redelivers whatever was not acked.
 type Consumer struct {
+	cfg       config.QueueConfig
 	conn      *amqp.Connection
 	channel   *amqp.Channel
+	closed    <-chan *amqp.Error
 	queueName string
 	handler   MessageHandler
 }
 
-func NewConsumer(url, queueName string, handler MessageHandler) (*Consumer, error) {
-	conn, err := amqp.Dial(url)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao conectar no RabbitMQ: %w", err)
+// NewConsumer connects to the queue. The first connection is not retried,
+// so a broken queue fails fast.
+func NewConsumer(cfg config.QueueConfig, handler MessageHandler) (*Consumer, error) {
+	c := &Consumer{
+		cfg:       cfg,
+		queueName: cfg.Name,
+		handler:   handler,
 	}
-
-	ch, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("falha ao abrir canal: %w", err)
+	if err := c.connect(); err != nil {
+		return nil, err
 	}
+	metrics.QueueConnectionUp.WithLabelValues("consumer").Set(1)
 
-	_, err = ch.QueueDeclare(
-		queueName,
-		true,
-		false,
-		false,
-		false,
-		nil,
+	slog.Info("consumer conectado ao RabbitMQ",
+		"queue", c.queueName,
 	)
-	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("falha ao declarar fila: %w", err)
-	}
 
-	// Process once
-	err = ch.Qos(
-		1,
-		0,
-		false,
-	)
+	return c, nil
+}
+
+// connect opens a connection and channel and declares the topology.
+func (c *Consumer) connect() error {
+	conn, ch, err := openChannel(c.cfg, func(ch *amqp.Channel) error {
+		if err := declareQueue(ch, c.queueName); err != nil {
+			return err
+		}
+		// Process once
+		if err := ch.Qos(1, 0, false); err != nil {
+			return fmt.Errorf("falha ao configurar QoS: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("falha ao configurar QoS: %w", err)
+		return err
 	}
 
-	slog.Info("consumer conectado ao RabbitMQ",
-		"queue", queueName,
-	)
-
-	return &Consumer{
-		conn:      conn,
-		channel:   ch,
-		queueName: queueName,
-		handler:   handler,
-	}, nil
+	c.conn, c.channel = conn, ch
+	c.closed = ch.NotifyClose(make(chan *amqp.Error, 1))
+	return nil
 }
 
+// Start consumes until ctx is cancelled, reconnecting whenever the
+// connection drops.
 func (c *Consumer) Start(ctx context.Context) error {
+	for {
+		err := c.consume(ctx)
+		if ctx.Err() != nil {
+			slog.Info("consumer encerrado pelo contexto")
+			return ctx.Err()
+		}
+		if !errors.Is(err, errConnectionLost) {
+			return err
+		}
+
+		slog.Warn("conexão do consumer com RabbitMQ perdida, reconectando", "error", err)
+		c.Close()
+		if !reconnect(ctx.Done(), "consumer", c.connect) {
+			slog.Info("consumer encerrado pelo contexto")
+			return ctx.Err()
+		}
+	}
+}
+
+// consume runs one session, returning errConnectionLost when its channel
+// closes.
+func (c *Consumer) consume(ctx context.Context) error {
 	msgs, err := c.channel.Consume(
 		c.queueName,
 		"",
@@ -80,6 +105,9 @@ func (c *Consumer) Start(ctx context.Context) error {
 		nil,
 	)
 	if err != nil {
+		if c.channel.IsClosed() {
+			return fmt.Errorf("%w: %v", errConnectionLost, err)
+		}
 		return fmt.Errorf("falha ao registrar consumer: %w", err)
 	}
 
@@ -88,12 +116,14 @@ func (c *Consumer) Start(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
-			slog.Info("consumer encerrado pelo contexto")
 			return ctx.Err()
 
+		case reason := <-c.closed:
+			return fmt.Errorf("%w: %v", errConnectionLost, reason)
+
 		case msg, ok := <-msgs:
 			if !ok {
-				return fmt.Errorf("canal de mensagens fechado")
+				return fmt.Errorf("%w: canal de mensagens fechado", errConnectionLost)
 			}
 
 			if err := c.processMessage(ctx, msg); err != nil {
@@ -101,8 +131,8 @@ func (c *Consumer) Start(ctx context.Context) error {
 					"error", err,
 					"body", string(msg.Body),
 				)
-				// Requeue
-				msg.Nack(false, true)
+				// Requeue, unless the message can never be processed
+				msg.Nack(false, !errors.Is(err, ErrUnsupportedMessage))
 			} else {
 				msg.Ack(false)
 			}
@@ -111,14 +141,19 @@ func (c *Consumer) Start(ctx context.Context) error {
 }
 
 func (c *Consumer) processMessage(ctx context.Context, msg amqp.Delivery) error {
-	var product domain.Product
-	if err := json.Unmarshal(msg.Body, &product); err != nil {
+	env, err := decodeEnvelope(msg.Body, msg.Timestamp)
+	if err != nil {
 		return fmt.Errorf("erro ao deserializar mensagem: %w", err)
 	}
+	product := env.Payload
 
 	slog.Info("processando produto",
+		"schema_version", env.SchemaVersion,
+		"message_id", env.MessageID,
+		"run_id", env.RunID,
+		"store", env.Store,
 		"title", product.Title,
-		"price", product.Price,
+		"price", product.Price.String(),
 		"category", product.Category,
 	)
 
@@ -129,14 +164,16 @@ func (c *Consumer) processMessage(ctx context.Context, msg amqp.Delivery) error
 	return nil
 }
 
+// Close closes the channel and the connection. The connection is closed
+// even when closing the channel fails, which is common after the broker
+// dropped it.
 func (c *Consumer) Close() error {
-	if c.channel != nil {
-		if err := c.channel.Close(); err != nil {
-			return err
-		}
+	var chErr, connErr error
+	if c.channel != nil && !c.channel.IsClosed() {
+		chErr = c.channel.Close()
 	}
-	if c.conn != nil {
-		return c.conn.Close()
+	if c.conn != nil && !c.conn.IsClosed() {
+		connErr = c.conn.Close()
 	}
-	return nil
+	return errors.Join(chErr, connErr)
 }
@@ -0,0 +1,109 @@
+package queue
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
+)
+
+// Delays between reconnection attempts, doubling from the first up to the
+// maximum.
+var (
+	reconnectDelay    = time.Second
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// dial connects to RabbitMQ, over TLS with the configured CA and client
+// certificate when cfg.TLS is enabled.
+func dial(cfg config.QueueConfig) (*amqp.Connection, error) {
+	tlsConfig, err := cfg.TLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao configurar TLS: %w", err)
+	}
+
+	var conn *amqp.Connection
+	if tlsConfig != nil {
+		conn, err = amqp.DialTLS(cfg.DialURL(), tlsConfig)
+	} else {
+		conn, err = amqp.Dial(cfg.DialURL())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar no RabbitMQ: %w", err)
+	}
+	return conn, nil
+}
+
+// openChannel dials, opens a channel and runs setup on it, which declares
+// the topology the caller needs. Both are closed if any step fails.
+func openChannel(cfg config.QueueConfig, setup func(*amqp.Channel) error) (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := dial(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("falha ao abrir canal: %w", err)
+	}
+
+	if err := setup(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, ch, nil
+}
+
+// declareQueue declares the durable product queue. It runs again after
+// every reconnect, in case the broker came back without it.
+func declareQueue(ch *amqp.Channel, name string) error {
+	_, err := ch.QueueDeclare(
+		name,
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("falha ao declarar fila: %w", err)
+	}
+	return nil
+}
+
+// reconnect calls open until it succeeds, waiting with exponential backoff
+// before each attempt. It returns false if stop is closed first. role labels
+// logs and metrics ("publisher" or "consumer").
+func reconnect(stop <-chan struct{}, role string, open func() error) bool {
+	metrics.QueueConnectionUp.WithLabelValues(role).Set(0)
+
+	delay := reconnectDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-stop:
+			return false
+		case <-time.After(delay):
+		}
+
+		if err := open(); err != nil {
+			delay = min(delay*2, reconnectMaxDelay)
+			slog.Warn("falha ao reconectar no RabbitMQ",
+				"role", role,
+				"attempt", attempt,
+				"retry_in", delay,
+				"error", err,
+			)
+			continue
+		}
+
+		metrics.QueueReconnects.WithLabelValues(role).Inc()
+		metrics.QueueConnectionUp.WithLabelValues(role).Set(1)
+		slog.Info("reconectado ao RabbitMQ", "role", role, "attempts", attempt)
+		return true
+	}
+}
@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+// SchemaVersion is the envelope version written by this producer. Version 1
+// is the legacy format, a bare domain.Product with no envelope; version 2
+// carried prices as floats instead of domain.Money.
+const SchemaVersion = 3
+
+// minSchemaVersion is the oldest envelope version the consumer accepts.
+const minSchemaVersion = 2
+
+// ProducerVersion identifies the build that published a message. Release
+// builds set it with -ldflags "-X .../internal/queue.ProducerVersion=v1.2.3".
+var ProducerVersion = "dev"
+
+// ErrUnsupportedMessage marks messages that can never be processed, such as
+// malformed JSON or a schema version newer than this consumer understands.
+var ErrUnsupportedMessage = errors.New("mensagem não suportada")
+
+// Envelope is the message published for each product.
+type Envelope struct {
+	SchemaVersion   int            `json:"schema_version"`
+	MessageID       string         `json:"message_id"`
+	RunID           string         `json:"run_id"`
+	Store           string         `json:"store"`
+	ScrapedAt       time.Time      `json:"scraped_at"`
+	ProducerVersion string         `json:"producer_version"`
+	Payload         domain.Product `json:"payload"`
+}
+
+// Run identifies one scrape (or replay) and the store it collected from.
+type Run struct {
+	ID    string
+	Store string
+}
+
+// NewRun returns a Run with a sortable, unique ID such as
+// "20240315T143022Z-3f9a1c2b".
+func NewRun(store string) Run {
+	return Run{
+		ID:    time.Now().UTC().Format("20060102T150405Z") + "-" + randomHex(4),
+		Store: store,
+	}
+}
+
+// NewEnvelope wraps product for run. Products without ScrapedAt, such as
+// those read back from a CSV, are stamped with the current time.
+func NewEnvelope(run Run, product domain.Product) Envelope {
+	scrapedAt := product.ScrapedAt
+	if scrapedAt.IsZero() {
+		scrapedAt = time.Now()
+	}
+	return Envelope{
+		SchemaVersion:   SchemaVersion,
+		MessageID:       newMessageID(),
+		RunID:           run.ID,
+		Store:           run.Store,
+		ScrapedAt:       scrapedAt.UTC(),
+		ProducerVersion: ProducerVersion,
+		Payload:         product,
+	}
+}
+
+// decodeEnvelope accepts both the current envelope and the legacy bare
+// product. Legacy messages take scraped_at from the AMQP timestamp, which
+// older publishers set at publish time. The returned payload always carries
+// ScrapedAt, and MessageID when the envelope has one.
+func decodeEnvelope(body []byte, timestamp time.Time) (Envelope, error) {
+	var probe struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return Envelope{}, fmt.Errorf("%w: %v", ErrUnsupportedMessage, err)
+	}
+
+	var env Envelope
+	switch {
+	case probe.SchemaVersion == nil:
+		if err := json.Unmarshal(body, &env.Payload); err != nil {
+			return Envelope{}, fmt.Errorf("%w: %v", ErrUnsupportedMessage, err)
+		}
+		env.SchemaVersion = 1
+		env.ScrapedAt = timestamp
+	case *probe.SchemaVersion >= minSchemaVersion && *probe.SchemaVersion <= SchemaVersion:
+		if err := json.Unmarshal(body, &env); err != nil {
+			return Envelope{}, fmt.Errorf("%w: %v", ErrUnsupportedMessage, err)
+		}
+	default:
+		return Envelope{}, fmt.Errorf("%w: versão de schema %d", ErrUnsupportedMessage, *probe.SchemaVersion)
+	}
+
+	if env.ScrapedAt.IsZero() {
+		env.ScrapedAt = time.Now()
+	}
+	env.Payload.ScrapedAt = env.ScrapedAt
+	env.Payload.MessageID = env.MessageID
+	return env, nil
+}
+
+// newMessageID returns a random UUID (version 4).
+func newMessageID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	scrapedAt := time.Date(2024, 3, 15, 14, 30, 22, 0, time.UTC)
+	product := domain.Product{
+		Title:     "Placa de Video ASUS RTX 4060, 8GB",
+		Price:     domain.BRL(189990),
+		Category:  "GPU",
+		ScrapedAt: scrapedAt,
+	}
+	run := Run{ID: "20240315T143000Z-0a1b2c3d", Store: "pichau"}
+
+	body, err := json.Marshal(NewEnvelope(run, product))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publishedAt := scrapedAt.Add(time.Hour)
+	env, err := decodeEnvelope(body, publishedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if env.SchemaVersion != SchemaVersion || env.RunID != run.ID || env.Store != run.Store {
+		t.Errorf("envelope = %+v", env)
+	}
+	if len(env.MessageID) != 36 || env.Payload.MessageID != env.MessageID {
+		t.Errorf("MessageID = %q / %q, want the same UUID", env.MessageID, env.Payload.MessageID)
+	}
+	if env.ProducerVersion != ProducerVersion {
+		t.Errorf("ProducerVersion = %q, want %q", env.ProducerVersion, ProducerVersion)
+	}
+	if !env.ScrapedAt.Equal(scrapedAt) || !env.Payload.ScrapedAt.Equal(scrapedAt) {
+		t.Errorf("ScrapedAt = %v / %v, want %v", env.ScrapedAt, env.Payload.ScrapedAt, scrapedAt)
+	}
+	if env.Payload.Title != product.Title || env.Payload.Price != product.Price {
+		t.Errorf("Payload = %+v, want %+v", env.Payload, product)
+	}
+}
+
+func TestDecodeLegacyProduct(t *testing.T) {
+	body := []byte(`{"Title":"Processador Ryzen 5 5600","Price":699,"Category":"CPU","Page":1}`)
+	publishedAt := time.Date(2024, 3, 15, 14, 30, 22, 0, time.UTC)
+
+	env, err := decodeEnvelope(body, publishedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if env.SchemaVersion != 1 {
+		t.Errorf("SchemaVersion = %d, want 1", env.SchemaVersion)
+	}
+	if env.Payload.Title != "Processador Ryzen 5 5600" || env.Payload.Price != domain.BRL(69900) {
+		t.Errorf("Payload = %+v", env.Payload)
+	}
+	if !env.Payload.ScrapedAt.Equal(publishedAt) {
+		t.Errorf("ScrapedAt = %v, want the AMQP timestamp %v", env.Payload.ScrapedAt, publishedAt)
+	}
+}
+
+func TestDecodeUnsupportedMessages(t *testing.T) {
+	for name, body := range map[string]string{
+		"malformed":      `{"Title":`,
+		"future version": `{"schema_version":4,"payload":{"Title":"x"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeEnvelope([]byte(body), time.Now())
+			if !errors.Is(err, ErrUnsupportedMessage) {
+				t.Errorf("err = %v, want ErrUnsupportedMessage", err)
+			}
+		})
+	}
+}
+
+func TestDecodeVersion2FloatPrices(t *testing.T) {
+	body := []byte(`{"schema_version":2,"message_id":"m","run_id":"r","store":"pichau",` +
+		`"scraped_at":"2024-03-15T14:30:22Z","payload":{"Title":"RTX 4060","Price":1899.9,"OriginalPrice":2199.99}}`)
+
+	env, err := decodeEnvelope(body, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Payload.Price != domain.BRL(189990) || env.Payload.OriginalPrice != domain.BRL(219999) {
+		t.Errorf("prices = %v / %v, want 1899.90 / 2199.99", env.Payload.Price, env.Payload.OriginalPrice)
+	}
+}
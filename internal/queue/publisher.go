@@ -3,97 +3,438 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"sync"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
 	"github.com/vitor-labes/pc-scraper/internal/domain"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
 )
 
+var (
+	ErrNacked          = errors.New("mensagem rejeitada pelo broker")
+	ErrUnroutable      = errors.New("mensagem devolvida pelo broker sem rota")
+	ErrPublisherClosed = errors.New("publisher fechado antes da confirmação")
+)
+
+// publishChannel is the part of *amqp.Channel the publisher uses, in confirm
+// mode.
+type publishChannel interface {
+	GetNextPublishSeqNo() uint64
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// publisherSession is one connection and confirm-mode channel with its
+// notification channels.
+type publisherSession struct {
+	channel  publishChannel
+	returns  <-chan amqp.Return
+	confirms <-chan amqp.Confirmation
+	closed   <-chan *amqp.Error
+	close    func() error
+}
+
+// Publisher publishes products with publisher confirms. Messages are
+// mandatory, so one the broker cannot route to the queue is returned and
+// reported as ErrUnroutable instead of being dropped.
+//
+// When the connection drops, the publisher reconnects with backoff and
+// republishes every message still awaiting confirmation, so a broker
+// restart may deliver a message twice. The copies share a message ID, and
+// the repository stores each message ID only once.
 type Publisher struct {
-	conn      *amqp.Connection
-	channel   *amqp.Channel
 	queueName string
+	run       Run
+	open      func() (*publisherSession, error)
+
+	// window holds a token per message awaiting confirmation.
+	window chan struct{}
+	// publishMu keeps delivery tags in step with publish order; reconnect
+	// holds it while republishing.
+	publishMu sync.Mutex
+
+	mu      sync.Mutex
+	session *publisherSession // nil while disconnected
+	// connected is closed while session is up.
+	connected chan struct{}
+	pending   map[uint64]*Confirmation
+	closing   bool
+	closed    chan struct{}
 }
 
-func NewPublisher(url, queueName string) (*Publisher, error) {
-	conn, err := amqp.Dial(url)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao conectar no RabbitMQ: %w", err)
+// Confirmation is the broker's answer for one published message.
+type Confirmation struct {
+	MessageID string
+	msg       amqp.Publishing
+	resolved  chan struct{}
+	err       error
+}
+
+// Wait blocks until the broker confirms the message, returning nil, or
+// rejects it, returns it or the publisher is closed first.
+func (c *Confirmation) Wait(ctx context.Context) error {
+	select {
+	case <-c.resolved:
+		return c.err
+	case <-ctx.Done():
+		return fmt.Errorf("aguardando confirmação: %w", ctx.Err())
 	}
+}
 
-	ch, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("falha ao abrir canal: %w", err)
+// NewPublisher connects to the queue. Every message it publishes is wrapped
+// in an Envelope tagged with run. The first connection is not retried, so
+// a broken queue fails fast.
+func NewPublisher(cfg config.QueueConfig, run Run) (*Publisher, error) {
+	p := newPublisher(cfg.Name, run, cfg.MaxInFlight, nil)
+	p.open = func() (*publisherSession, error) {
+		return openPublisherSession(cfg, cap(p.window))
 	}
 
-	_, err = ch.QueueDeclare(
-		queueName,
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
+	s, err := p.open()
 	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("falha ao declarar fila: %w", err)
+		return nil, err
 	}
+	p.attach(s)
 
 	slog.Info("publisher conectado ao RabbitMQ",
-		"queue", queueName,
+		"queue", cfg.Name,
+		"run_id", run.ID,
+		"max_in_flight", cap(p.window),
 	)
+	return p, nil
+}
 
+func newPublisher(queueName string, run Run, maxInFlight int, open func() (*publisherSession, error)) *Publisher {
 	return &Publisher{
-		conn:      conn,
-		channel:   ch,
 		queueName: queueName,
+		run:       run,
+		open:      open,
+		window:    make(chan struct{}, max(maxInFlight, 1)),
+		connected: make(chan struct{}),
+		pending:   make(map[uint64]*Confirmation),
+		closed:    make(chan struct{}),
+	}
+}
+
+func openPublisherSession(cfg config.QueueConfig, window int) (*publisherSession, error) {
+	conn, ch, err := openChannel(cfg, func(ch *amqp.Channel) error {
+		if err := declareQueue(ch, cfg.Name); err != nil {
+			return err
+		}
+		if err := ch.Confirm(false); err != nil {
+			return fmt.Errorf("falha ao ativar confirmações: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &publisherSession{
+		channel: ch,
+		// Returns must be unbuffered: the library then hands each one over
+		// before dispatching the ack for the same message.
+		returns:  ch.NotifyReturn(make(chan amqp.Return)),
+		confirms: ch.NotifyPublish(make(chan amqp.Confirmation, window)),
+		closed:   ch.NotifyClose(make(chan *amqp.Error, 1)),
+		close: func() error {
+			ch.Close()
+			return conn.Close()
+		},
 	}, nil
 }
 
-func (p *Publisher) Publish(ctx context.Context, product domain.Product) error {
-	body, err := json.Marshal(product)
+// attach makes s the active session, unless the publisher was closed
+// meanwhile.
+func (p *Publisher) attach(s *publisherSession) {
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		s.close()
+		p.failPending()
+		return
+	}
+	p.session = s
+	close(p.connected)
+	p.mu.Unlock()
+
+	metrics.QueueConnectionUp.WithLabelValues("publisher").Set(1)
+	go p.handleConfirms(s)
+}
+
+// Publish sends product and returns without waiting for the broker; use
+// the Confirmation to learn the outcome. It blocks while MaxInFlight
+// messages await confirmation and while reconnecting.
+func (p *Publisher) Publish(ctx context.Context, product domain.Product) (*Confirmation, error) {
+	env := NewEnvelope(p.run, product)
+	body, err := json.Marshal(env)
 	if err != nil {
-		return fmt.Errorf("erro ao serializar produto: %w", err)
+		return nil, fmt.Errorf("erro ao serializar produto: %w", err)
 	}
 
-	err = p.channel.PublishWithContext(
-		ctx,
-		"",
-		p.queueName,
-		false,
-		false,
-		amqp.Publishing{
+	select {
+	case p.window <- struct{}{}:
+		metrics.MessagesInFlight.Inc()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("aguardando janela de publicação: %w", ctx.Err())
+	}
+
+	c := &Confirmation{
+		MessageID: env.MessageID,
+		resolved:  make(chan struct{}),
+		msg: amqp.Publishing{
 			DeliveryMode: amqp.Persistent,
 			ContentType:  "application/json",
+			Type:         "product",
+			MessageId:    env.MessageID,
+			AppId:        "pc-scraper/" + env.ProducerVersion,
 			Body:         body,
 			Timestamp:    time.Now(),
 		},
-	)
+	}
 
-	if err != nil {
-		return fmt.Errorf("erro ao publicar mensagem: %w", err)
+	for {
+		p.mu.Lock()
+		connected := p.connected
+		p.mu.Unlock()
+
+		select {
+		case <-connected:
+		case <-p.closed:
+			p.release()
+			return nil, ErrPublisherClosed
+		case <-ctx.Done():
+			p.release()
+			return nil, fmt.Errorf("aguardando reconexão: %w", ctx.Err())
+		}
+
+		p.publishMu.Lock()
+		p.mu.Lock()
+		s := p.session
+		p.mu.Unlock()
+		if s == nil {
+			// Lost again before we got the lock.
+			p.publishMu.Unlock()
+			continue
+		}
+		err := p.send(ctx, s, c)
+		p.publishMu.Unlock()
+
+		if err != nil {
+			p.release()
+			return nil, fmt.Errorf("erro ao publicar mensagem: %w", err)
+		}
+		break
 	}
 
+	metrics.MessagesPublished.Inc()
+
 	slog.Debug("produto publicado",
+		"message_id", env.MessageID,
 		"title", product.Title,
-		"price", product.Price,
+		"price", product.Price.String(),
 	)
 
-	return nil
+	return c, nil
 }
 
-func (p *Publisher) Close() error {
-	if p.channel != nil {
-		if err := p.channel.Close(); err != nil {
-			return err
+// send publishes c on s and tracks it by delivery tag. If the channel is
+// already closed, c stays pending and is republished after reconnecting.
+// The caller holds publishMu.
+func (p *Publisher) send(ctx context.Context, s *publisherSession, c *Confirmation) error {
+	p.mu.Lock()
+	tag := s.channel.GetNextPublishSeqNo()
+	p.pending[tag] = c
+	p.mu.Unlock()
+
+	err := s.channel.PublishWithContext(ctx, "", p.queueName, true, false, c.msg)
+	if err == nil || errors.Is(err, amqp.ErrClosed) {
+		return nil
+	}
+
+	p.mu.Lock()
+	delete(p.pending, tag)
+	p.mu.Unlock()
+	return err
+}
+
+// handleConfirms resolves pending messages as acks, nacks and returns
+// arrive, until the session's channel closes. A returned message is still
+// acked by the broker, so returns are remembered by message ID until its
+// ack.
+func (p *Publisher) handleConfirms(s *publisherSession) {
+	returned := make(map[string]amqp.Return)
+	returns, closed := s.returns, s.closed
+	var reason *amqp.Error
+
+	for {
+		select {
+		case r, ok := <-returns:
+			if !ok {
+				returns = nil
+				continue
+			}
+			returned[r.MessageId] = r
+
+		case err, ok := <-closed:
+			// Keep reading confirms the broker sent before closing; the
+			// library closes that channel too.
+			if ok {
+				reason = err
+			}
+			closed = nil
+
+		case conf, ok := <-s.confirms:
+			if !ok {
+				p.lost(s, reason)
+				return
+			}
+			p.confirm(conf, returned)
+		}
+	}
+}
+
+func (p *Publisher) confirm(conf amqp.Confirmation, returned map[string]amqp.Return) {
+	p.mu.Lock()
+	c := p.pending[conf.DeliveryTag]
+	delete(p.pending, conf.DeliveryTag)
+	p.mu.Unlock()
+	if c == nil {
+		return
+	}
+
+	r, wasReturned := returned[c.MessageID]
+	delete(returned, c.MessageID)
+	switch {
+	case !conf.Ack:
+		metrics.MessagesNacked.Inc()
+		c.err = ErrNacked
+	case wasReturned:
+		metrics.MessagesReturned.Inc()
+		c.err = fmt.Errorf("%w: %s (%d)", ErrUnroutable, r.ReplyText, r.ReplyCode)
+	default:
+		metrics.MessagesConfirmed.Inc()
+	}
+	p.resolve(c)
+}
+
+// lost handles the end of session s: after Close it fails what is still
+// pending, otherwise it starts reconnecting.
+func (p *Publisher) lost(s *publisherSession, reason *amqp.Error) {
+	p.mu.Lock()
+	if p.session == s {
+		p.session = nil
+		p.connected = make(chan struct{})
+	}
+	closing := p.closing
+	pending := len(p.pending)
+	p.mu.Unlock()
+
+	metrics.QueueConnectionUp.WithLabelValues("publisher").Set(0)
+	if closing {
+		p.failPending()
+		return
+	}
+
+	slog.Warn("conexão do publisher com RabbitMQ perdida, reconectando",
+		"pending", pending,
+		"error", reason,
+	)
+	go p.reconnect()
+}
+
+// reconnect opens a new session with backoff, republishes the messages
+// that were awaiting confirmation in their original order, and resumes
+// publishing.
+func (p *Publisher) reconnect() {
+	var s *publisherSession
+	ok := reconnect(p.closed, "publisher", func() (err error) {
+		s, err = p.open()
+		return err
+	})
+	if !ok {
+		p.failPending()
+		return
+	}
+
+	p.publishMu.Lock()
+	defer p.publishMu.Unlock()
+
+	p.mu.Lock()
+	old := p.pending
+	p.pending = make(map[uint64]*Confirmation)
+	p.mu.Unlock()
+
+	tags := make([]uint64, 0, len(old))
+	for tag := range old {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	// If the new channel fails too, its messages stay pending for the
+	// next reconnect.
+	for _, tag := range tags {
+		c := old[tag]
+		if err := p.send(context.Background(), s, c); err != nil {
+			slog.Error("erro ao republicar mensagem", "message_id", c.MessageID, "error", err)
+			c.err = err
+			p.resolve(c)
 		}
 	}
-	if p.conn != nil {
-		return p.conn.Close()
+
+	slog.Info("publisher retomado", "republished", len(tags))
+	p.attach(s)
+}
+
+// failPending resolves every message still awaiting confirmation with
+// ErrPublisherClosed; the broker may or may not have stored them.
+func (p *Publisher) failPending() {
+	p.mu.Lock()
+	pending := p.pending
+	p.pending = make(map[uint64]*Confirmation)
+	p.mu.Unlock()
+
+	if len(pending) > 0 {
+		slog.Warn("publisher fechado com mensagens sem confirmação", "pending", len(pending))
+	}
+	for _, c := range pending {
+		c.err = ErrPublisherClosed
+		p.resolve(c)
+	}
+}
+
+func (p *Publisher) resolve(c *Confirmation) {
+	close(c.resolved)
+	p.release()
+}
+
+// release frees the window slot taken in Publish.
+func (p *Publisher) release() {
+	<-p.window
+	metrics.MessagesInFlight.Dec()
+}
+
+// Close stops reconnecting and closes the connection. Messages still
+// awaiting confirmation fail with ErrPublisherClosed, so wait for them
+// first.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closing = true
+	close(p.closed)
+	s := p.session
+	p.mu.Unlock()
+
+	if s != nil {
+		return s.close()
 	}
 	return nil
 }
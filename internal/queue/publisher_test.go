@@ -0,0 +1,259 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+// fakeChannel records publishes and numbers them like a channel in confirm
+// mode.
+type fakeChannel struct {
+	mu        sync.Mutex
+	next      uint64
+	closed    bool
+	published []amqp.Publishing
+	mandatory []bool
+}
+
+func (f *fakeChannel) GetNextPublishSeqNo() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.next + 1
+}
+
+func (f *fakeChannel) PublishWithContext(_ context.Context, _, _ string, mandatory, _ bool, msg amqp.Publishing) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return amqp.ErrClosed
+	}
+	f.next++
+	f.published = append(f.published, msg)
+	f.mandatory = append(f.mandatory, mandatory)
+	return nil
+}
+
+func (f *fakeChannel) messageIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, len(f.published))
+	for i, msg := range f.published {
+		ids[i] = msg.MessageId
+	}
+	return ids
+}
+
+// fakeSession stands in for a connection; drop simulates the broker going
+// away.
+type fakeSession struct {
+	channel  *fakeChannel
+	returns  chan amqp.Return
+	confirms chan amqp.Confirmation
+	closed   chan *amqp.Error
+	once     sync.Once
+}
+
+func (f *fakeSession) drop(reason *amqp.Error) {
+	f.once.Do(func() {
+		f.channel.mu.Lock()
+		f.channel.closed = true
+		f.channel.mu.Unlock()
+		if reason != nil {
+			f.closed <- reason
+		}
+		close(f.closed)
+		close(f.returns)
+		close(f.confirms)
+	})
+}
+
+func (f *fakeSession) ack(tag uint64, ack bool) {
+	f.confirms <- amqp.Confirmation{DeliveryTag: tag, Ack: ack}
+}
+
+// startPublisher returns a connected publisher and a channel receiving
+// every session it opens, the first one included.
+func startPublisher(t *testing.T, maxInFlight int) (*Publisher, chan *fakeSession) {
+	t.Helper()
+	opened := make(chan *fakeSession, 4)
+	open := func() (*publisherSession, error) {
+		f := &fakeSession{
+			channel:  &fakeChannel{},
+			returns:  make(chan amqp.Return),
+			confirms: make(chan amqp.Confirmation, maxInFlight),
+			closed:   make(chan *amqp.Error, 1),
+		}
+		opened <- f
+		return &publisherSession{
+			channel:  f.channel,
+			returns:  f.returns,
+			confirms: f.confirms,
+			closed:   f.closed,
+			close:    func() error { f.drop(nil); return nil },
+		}, nil
+	}
+
+	p := newPublisher("product_prices", Run{ID: "run", Store: "pichau"}, maxInFlight, open)
+	s, err := open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.attach(s)
+	return p, opened
+}
+
+func nextSession(t *testing.T, opened chan *fakeSession) *fakeSession {
+	t.Helper()
+	select {
+	case f := <-opened:
+		return f
+	case <-time.After(time.Second):
+		t.Fatal("no session opened")
+		return nil
+	}
+}
+
+func publish(t *testing.T, p *Publisher, title string) *Confirmation {
+	t.Helper()
+	c, err := p.Publish(context.Background(), domain.Product{Title: title, Price: domain.BRL(100)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func waitResult(t *testing.T, c *Confirmation) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return c.Wait(ctx)
+}
+
+func TestPublisherConfirmOutcomes(t *testing.T) {
+	p, opened := startPublisher(t, 10)
+	s := nextSession(t, opened)
+
+	acked := publish(t, p, "a")
+	nacked := publish(t, p, "b")
+	unroutable := publish(t, p, "c")
+
+	// The broker sends basic.return before the ack of the same message.
+	s.returns <- amqp.Return{MessageId: unroutable.MessageID, ReplyCode: 312, ReplyText: "NO_ROUTE"}
+	s.ack(1, true)
+	s.ack(2, false)
+	s.ack(3, true)
+
+	if err := waitResult(t, acked); err != nil {
+		t.Errorf("acked: %v", err)
+	}
+	if err := waitResult(t, nacked); !errors.Is(err, ErrNacked) {
+		t.Errorf("nacked: %v, want ErrNacked", err)
+	}
+	if err := waitResult(t, unroutable); !errors.Is(err, ErrUnroutable) {
+		t.Errorf("unroutable: %v, want ErrUnroutable", err)
+	}
+
+	for i, msg := range s.channel.published {
+		if !s.channel.mandatory[i] || msg.MessageId == "" || msg.DeliveryMode != amqp.Persistent {
+			t.Errorf("publish %d: mandatory=%v message_id=%q delivery_mode=%d",
+				i, s.channel.mandatory[i], msg.MessageId, msg.DeliveryMode)
+		}
+	}
+}
+
+func TestPublisherWindowBlocksUntilConfirm(t *testing.T) {
+	p, opened := startPublisher(t, 2)
+	s := nextSession(t, opened)
+
+	publish(t, p, "a")
+	publish(t, p, "b")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.Publish(ctx, domain.Product{Title: "c"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Publish with a full window = %v, want DeadlineExceeded", err)
+	}
+
+	s.ack(1, true)
+	c := publish(t, p, "c")
+
+	s.ack(2, true)
+	s.ack(3, true)
+	if err := waitResult(t, c); err != nil {
+		t.Errorf("c: %v", err)
+	}
+}
+
+func TestPublisherCloseFailsPending(t *testing.T) {
+	p, opened := startPublisher(t, 10)
+	nextSession(t, opened)
+
+	c := publish(t, p, "a")
+	p.Close()
+
+	if err := waitResult(t, c); !errors.Is(err, ErrPublisherClosed) {
+		t.Errorf("pending: %v, want ErrPublisherClosed", err)
+	}
+	if _, err := p.Publish(context.Background(), domain.Product{Title: "b"}); !errors.Is(err, ErrPublisherClosed) {
+		t.Errorf("Publish after Close = %v, want ErrPublisherClosed", err)
+	}
+}
+
+func TestPublisherReconnectsAndRepublishesPending(t *testing.T) {
+	defer func(d time.Duration) { reconnectDelay = d }(reconnectDelay)
+	reconnectDelay = time.Millisecond
+
+	p, opened := startPublisher(t, 10)
+	defer p.Close()
+	first := nextSession(t, opened)
+
+	a := publish(t, p, "a")
+	b := publish(t, p, "b")
+	first.ack(1, true)
+	first.drop(&amqp.Error{Code: amqp.ConnectionForced, Reason: "broker restart"})
+
+	second := nextSession(t, opened)
+	// Publishing waits for the reconnect to finish.
+	c := publish(t, p, "c")
+
+	if got := second.channel.messageIDs(); len(got) != 2 || got[0] != b.MessageID || got[1] != c.MessageID {
+		t.Fatalf("published after reconnect = %v, want [%s %s]", got, b.MessageID, c.MessageID)
+	}
+
+	second.ack(1, true)
+	second.ack(2, true)
+	for name, conf := range map[string]*Confirmation{"a": a, "b": b, "c": c} {
+		if err := waitResult(t, conf); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestReconnectRetriesUntilOpen(t *testing.T) {
+	defer func(d time.Duration) { reconnectDelay = d }(reconnectDelay)
+	reconnectDelay = time.Millisecond
+
+	calls := 0
+	ok := reconnect(make(chan struct{}), "publisher", func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if !ok || calls != 3 {
+		t.Errorf("reconnect() = %v after %d calls, want true after 3", ok, calls)
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	if reconnect(stop, "publisher", func() error { return errors.New("unreachable") }) {
+		t.Error("reconnect() = true after stop, want false")
+	}
+}
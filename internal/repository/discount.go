@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+// DiscountCheck compares the discount a store advertises with the discount
+// relative to the median price we observed over the history window.
+type DiscountCheck struct {
+	Title              string
+	Category           string
+	Price              domain.Money
+	OriginalPrice      domain.Money
+	AdvertisedDiscount float64
+	CouponCode         string
+	ReferencePrice     domain.Money
+	RealDiscount       float64
+}
+
+// DiscountChecks returns the latest advertised promotions of a category,
+// largest gap between advertised and real discount first. The reference
+// price leaves the latest observation out, so a promotion does not lower
+// the baseline it is measured against.
+func (r *ProductRepository) DiscountChecks(ctx context.Context, category string, historyDays int) ([]DiscountCheck, error) {
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (title)
+				id, title, category, price, original_price, advertised_discount, coupon_code
+			FROM products
+			WHERE category = $1
+			ORDER BY title, scraped_at DESC
+		),
+		history AS (
+			SELECT p.title, percentile_cont(0.5) WITHIN GROUP (ORDER BY p.price) AS reference_price
+			FROM products p
+			JOIN latest l ON l.title = p.title
+			WHERE p.category = $1
+				AND p.id <> l.id
+				AND p.scraped_at >= NOW() - make_interval(days => $2)
+			GROUP BY p.title
+		)
+		SELECT
+			l.title,
+			l.category,
+			l.price,
+			l.original_price,
+			l.advertised_discount,
+			l.coupon_code,
+			h.reference_price,
+			(1 - l.price / NULLIF(h.reference_price, 0)) * 100 AS real_discount
+		FROM latest l
+		JOIN history h ON h.title = l.title
+		WHERE l.advertised_discount IS NOT NULL
+		ORDER BY l.advertised_discount - COALESCE((1 - l.price / NULLIF(h.reference_price, 0)) * 100, 0) DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, category, historyDays)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar descontos: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []DiscountCheck
+	for rows.Next() {
+		var (
+			c            DiscountCheck
+			coupon       sql.NullString
+			realDiscount sql.NullFloat64
+		)
+		if err := rows.Scan(
+			&c.Title,
+			&c.Category,
+			&c.Price,
+			&c.OriginalPrice,
+			&c.AdvertisedDiscount,
+			&coupon,
+			&c.ReferencePrice,
+			&realDiscount,
+		); err != nil {
+			return nil, fmt.Errorf("erro ao escanear linha: %w", err)
+		}
+		c.CouponCode = coupon.String
+		c.RealDiscount = realDiscount.Float64
+		checks = append(checks, c)
+	}
+
+	return checks, rows.Err()
+}
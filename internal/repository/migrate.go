@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// Migrate runs a schema script (e.g. scripts/init.sql) in one transaction.
+// The script must be idempotent so it can run against existing databases.
+func (r *ProductRepository) Migrate(ctx context.Context, script string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("erro ao aplicar migração: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar migração: %w", err)
+	}
+	return nil
+}
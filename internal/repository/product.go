@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/vitor-labes/pc-scraper/internal/domain"
@@ -34,13 +37,25 @@ func NewProductRepository(databaseURL string) (*ProductRepository, error) {
 
 func (r *ProductRepository) Save(ctx context.Context, product domain.Product) error {
 	query := `
-		INSERT INTO products (title, brand, price, raw_price, page_number, category)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO products (
+			title, brand, price, raw_price, page_number, category, attributes,
+			original_price, advertised_discount, coupon_code, coupon_condition,
+			condition, is_bundle, rating, review_count, scraped_at, currency,
+			message_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			COALESCE($16, CURRENT_TIMESTAMP), $17, $18)
+		ON CONFLICT (message_id) DO NOTHING
 		RETURNING id
 	`
 
+	attributes, err := marshalAttributes(product.Attributes)
+	if err != nil {
+		return err
+	}
+
 	var id int
-	err := r.db.QueryRowContext(
+	err = r.db.QueryRowContext(
 		ctx,
 		query,
 		product.Title,
@@ -49,8 +64,28 @@ func (r *ProductRepository) Save(ctx context.Context, product domain.Product) er
 		product.RawPrice,
 		product.Page,
 		product.Category,
+		attributes,
+		nullMoney(product.OriginalPrice),
+		nullFloat(product.AdvertisedDiscount),
+		nullString(product.CouponCode),
+		nullString(product.CouponCondition),
+		productCondition(product),
+		product.Bundle,
+		nullFloat(product.Rating),
+		sql.NullInt64{Int64: int64(product.ReviewCount), Valid: product.ReviewCount > 0},
+		nullTime(product.ScrapedAt),
+		productCurrency(product),
+		nullString(product.MessageID),
 	).Scan(&id)
 
+	// A redelivered or republished message was already stored.
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Info("mensagem duplicada ignorada",
+			"message_id", product.MessageID,
+			"title", product.Title,
+		)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("erro ao inserir produto: %w", err)
 	}
@@ -58,22 +93,68 @@ func (r *ProductRepository) Save(ctx context.Context, product domain.Product) er
 	slog.Info("produto salvo no banco",
 		"id", id,
 		"title", product.Title,
-		"price", product.Price,
+		"price", product.Price.String(),
 	)
 
 	return nil
 }
 
-func (r *ProductRepository) FindBestPrices(ctx context.Context, category string) ([]domain.Product, error) {
+func marshalAttributes(attributes map[string]string) (interface{}, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar atributos: %w", err)
+	}
+	return string(data), nil
+}
+
+// productCondition defaults messages from older scrapers to "new".
+func productCondition(product domain.Product) string {
+	if product.Condition == "" {
+		return domain.ConditionNew
+	}
+	return product.Condition
+}
+
+// productCurrency defaults messages from older scrapers to BRL.
+func productCurrency(product domain.Product) string {
+	if product.Price.Currency == "" {
+		return domain.CurrencyBRL
+	}
+	return product.Price.Currency
+}
+
+func nullMoney(v domain.Money) sql.NullString {
+	return sql.NullString{String: v.String(), Valid: !v.IsZero()}
+}
+
+func nullFloat(v float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: v, Valid: v != 0}
+}
+
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
+
+// nullTime leaves zero times NULL, for the column default to fill in.
+func nullTime(v time.Time) sql.NullTime {
+	return sql.NullTime{Time: v, Valid: !v.IsZero()}
+}
+
+// FindBestPrices returns up to limit of the cheapest new, non-bundle
+// products of a category.
+func (r *ProductRepository) FindBestPrices(ctx context.Context, category string, limit int) ([]domain.Product, error) {
 	query := `
 		SELECT title, category, price, raw_price
 		FROM v_best_prices
 		WHERE category = $1
 		ORDER BY price ASC
-		LIMIT 20
+		LIMIT $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, category)
+	rows, err := r.db.QueryContext(ctx, query, category, limit)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar melhores preços: %w", err)
 	}
@@ -88,7 +169,70 @@ func (r *ProductRepository) FindBestPrices(ctx context.Context, category string)
 		products = append(products, p)
 	}
 
-	return products, nil
+	return products, rows.Err()
+}
+
+// LatestProducts returns the most recent observation of every product,
+// optionally limited to one category.
+func (r *ProductRepository) LatestProducts(ctx context.Context, category string) ([]domain.Product, error) {
+	query := `
+		SELECT DISTINCT ON (title, category)
+			title, COALESCE(brand, ''), price, COALESCE(raw_price, ''),
+			COALESCE(page_number, 0), category, attributes,
+			original_price, advertised_discount, coupon_code, coupon_condition,
+			condition, is_bundle, rating, COALESCE(review_count, 0), currency,
+			scraped_at
+		FROM products
+		WHERE $1 = '' OR category = $1
+		ORDER BY title, category, scraped_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, category)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar produtos: %w", err)
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		var (
+			p                           domain.Product
+			attributes                  []byte
+			discount                    sql.NullFloat64
+			couponCode, couponCondition sql.NullString
+			rating                      sql.NullFloat64
+			currency                    string
+			scrapedAt                   sql.NullTime
+		)
+		if err := rows.Scan(
+			&p.Title, &p.Brand, &p.Price, &p.RawPrice,
+			&p.Page, &p.Category, &attributes,
+			&p.OriginalPrice, &discount, &couponCode, &couponCondition,
+			&p.Condition, &p.Bundle, &rating, &p.ReviewCount, &currency,
+			&scrapedAt,
+		); err != nil {
+			return nil, fmt.Errorf("erro ao escanear linha: %w", err)
+		}
+
+		if len(attributes) > 0 {
+			if err := json.Unmarshal(attributes, &p.Attributes); err != nil {
+				return nil, fmt.Errorf("erro ao decodificar atributos: %w", err)
+			}
+		}
+		p.Price.Currency = currency
+		if !p.OriginalPrice.IsZero() {
+			p.OriginalPrice.Currency = currency
+		}
+		p.AdvertisedDiscount = discount.Float64
+		p.CouponCode = couponCode.String
+		p.CouponCondition = couponCondition.String
+		p.Rating = rating.Float64
+		p.ScrapedAt = scrapedAt.Time
+
+		products = append(products, p)
+	}
+
+	return products, rows.Err()
 }
 
 func (r *ProductRepository) GetStats(ctx context.Context) (map[string]interface{}, error) {
@@ -98,16 +242,16 @@ func (r *ProductRepository) GetStats(ctx context.Context) (map[string]interface{
 			COUNT(DISTINCT category) as categories,
 			MIN(price) as min_price,
 			MAX(price) as max_price,
-			AVG(price) as avg_price
+			ROUND(AVG(price), 2) as avg_price
 		FROM products
 	`
 
 	var stats struct {
 		Total      int
 		Categories int
-		MinPrice   float64
-		MaxPrice   float64
-		AvgPrice   float64
+		MinPrice   domain.Money
+		MaxPrice   domain.Money
+		AvgPrice   domain.Money
 	}
 
 	err := r.db.QueryRowContext(ctx, query).Scan(
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+// RankedProduct is a product with its review-adjusted ranking data.
+type RankedProduct struct {
+	domain.Product
+	WeightedRating float64
+	EffectivePrice domain.Money
+}
+
+// FindBestRated ranks the latest new, non-bundle products of a category by
+// an effective price that favours well-reviewed models. The rating is a
+// Bayesian average pulled towards 3 stars (weight of 10 reviews), so a few
+// reviews move it little; a 5-star average lowers the effective price by up
+// to ratingWeight (e.g. 0.05 = 5%) and a 1-star one raises it by the same.
+func (r *ProductRepository) FindBestRated(ctx context.Context, category string, ratingWeight float64, limit int) ([]RankedProduct, error) {
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (title)
+				title, category, brand, price, raw_price, rating, review_count
+			FROM products
+			WHERE category = $1 AND condition = 'new' AND NOT is_bundle
+			ORDER BY title, scraped_at DESC
+		),
+		scored AS (
+			SELECT *,
+				(10 * 3.0 + COALESCE(rating, 3) * COALESCE(review_count, 0))
+					/ (10 + COALESCE(review_count, 0)) AS weighted_rating
+			FROM latest
+		)
+		SELECT
+			title, category, brand, price, raw_price,
+			COALESCE(rating, 0), COALESCE(review_count, 0),
+			weighted_rating,
+			ROUND(price * (1 - $2 * (weighted_rating - 3) / 2), 2) AS effective_price
+		FROM scored
+		ORDER BY effective_price ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, category, ratingWeight, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ranking: %w", err)
+	}
+	defer rows.Close()
+
+	var ranked []RankedProduct
+	for rows.Next() {
+		var p RankedProduct
+		if err := rows.Scan(
+			&p.Title,
+			&p.Category,
+			&p.Brand,
+			&p.Price,
+			&p.RawPrice,
+			&p.Rating,
+			&p.ReviewCount,
+			&p.WeightedRating,
+			&p.EffectivePrice,
+		); err != nil {
+			return nil, fmt.Errorf("erro ao escanear linha: %w", err)
+		}
+		ranked = append(ranked, p)
+	}
+
+	return ranked, rows.Err()
+}
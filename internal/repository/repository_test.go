@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+// newTestRepository connects to TEST_DATABASE_URL and applies
+// scripts/init.sql in a throwaway schema, dropped when the test ends.
+func newTestRepository(t *testing.T) *ProductRepository {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL não definido")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("pc_scraper_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("erro ao criar schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	sep := " "
+	if strings.Contains(dsn, "://") {
+		sep = "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+	}
+	repo, err := NewProductRepository(dsn + sep + "search_path=" + schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	script, err := os.ReadFile("../../scripts/init.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Migrate(context.Background(), string(script)); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestDiscountChecksExcludesLatestObservation(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for i, cents := range []int64{200000, 180000} {
+		product := domain.Product{
+			Title: "Placa de Video RTX 4060", Category: "GPU",
+			Price:     domain.BRL(cents),
+			ScrapedAt: now.Add(-time.Duration(i+1) * 24 * time.Hour),
+		}
+		if err := repo.Save(ctx, product); err != nil {
+			t.Fatal(err)
+		}
+	}
+	promo := domain.Product{
+		Title: "Placa de Video RTX 4060", Category: "GPU",
+		Price:              domain.BRL(150000),
+		OriginalPrice:      domain.BRL(214290),
+		AdvertisedDiscount: 30,
+		ScrapedAt:          now,
+	}
+	if err := repo.Save(ctx, promo); err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err := repo.DiscountChecks(ctx, "GPU", 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 1 {
+		t.Fatalf("DiscountChecks() = %+v, want one check", checks)
+	}
+
+	// The median of 2000.00 and 1800.00, without today's 1500.00.
+	c := checks[0]
+	if c.ReferencePrice != domain.BRL(190000) {
+		t.Errorf("ReferencePrice = %s, want 1900.00", c.ReferencePrice)
+	}
+	if math.Abs(c.RealDiscount-21.05) > 0.01 || c.AdvertisedDiscount != 30 {
+		t.Errorf("desconto real/anunciado = %.2f/%.2f, want 21.05/30", c.RealDiscount, c.AdvertisedDiscount)
+	}
+}
+
+func TestFindBestPricesHonoursLimit(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	for i := 0; i < 25; i++ {
+		product := domain.Product{
+			Title:    fmt.Sprintf("SSD NVMe 1TB Modelo %02d", i),
+			Category: "SSD",
+			Price:    domain.BRL(int64(40000 + i*100)),
+		}
+		if err := repo.Save(ctx, product); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	products, err := repo.FindBestPrices(ctx, "SSD", 22)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != 22 {
+		t.Fatalf("FindBestPrices() = %d produtos, want 22", len(products))
+	}
+	if products[0].Price != domain.BRL(40000) {
+		t.Errorf("primeiro preço = %s, want 400.00", products[0].Price)
+	}
+}
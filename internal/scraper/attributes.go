@@ -0,0 +1,152 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Category kinds with a dedicated title parser.
+const (
+	kindGPU         = "gpu"
+	kindCPU         = "cpu"
+	kindMotherboard = "motherboard"
+	kindRAM         = "ram"
+	kindSSD         = "ssd"
+	kindPSU         = "psu"
+	kindCase        = "case"
+)
+
+var (
+	gpuChipsetPattern  = regexp.MustCompile(`(?i)\b(RTX|GTX|RX|ARC)\s?-?([A-Z]?\d{3,4})\s?(TI SUPER|TI|SUPER|XTX|XT|GRE)?\b`)
+	vramPattern        = regexp.MustCompile(`(?i)\b(\d{1,2})\s?GB\b`)
+	intelCPUPattern    = regexp.MustCompile(`(?i)\b(?:CORE\s+)?(I[3579]|ULTRA\s+[3579])[\s-]+(\d{3,5}[A-Z]{0,3})\b`)
+	ryzenCPUPattern    = regexp.MustCompile(`(?i)\bRYZEN\s+(?:THREADRIPPER\s+)?([3579])\s+(\d{4}[A-Z0-9]{0,4})\b`)
+	socketPattern      = regexp.MustCompile(`(?i)\b(AM[45]|LGA\s?-?\d{4}|TR[45]|STR5)\b`)
+	chipsetPattern     = regexp.MustCompile(`(?i)\b([ABHXZ]\d{3}E?)M?\b`)
+	formFactorPattern  = regexp.MustCompile(`(?i)\b(E-ATX|MICRO[\s-]?ATX|M-ATX|MATX|MINI[\s-]?ITX|ITX|ATX)\b`)
+	ramTypePattern     = regexp.MustCompile(`(?i)\b(DDR[345])\b`)
+	ramKitPattern      = regexp.MustCompile(`(?i)\b(\d)\s?X\s?(\d{1,3})\s?GB\b`)
+	speedPattern       = regexp.MustCompile(`(?i)\b(\d{4})\s?(?:MHZ|MT/S)\b`)
+	storagePattern     = regexp.MustCompile(`(?i)\b(\d+(?:[.,]\d+)?)\s?(TB|GB)\b`)
+	nvmePattern        = regexp.MustCompile(`(?i)\bNVME\b`)
+	sataPattern        = regexp.MustCompile(`(?i)\bSATA\b`)
+	m2Pattern          = regexp.MustCompile(`(?i)\bM\.2\b`)
+	wattagePattern     = regexp.MustCompile(`(?i)\b(\d{3,4})\s?W\b`)
+	certificatePattern = regexp.MustCompile(`(?i)\b80\s?PLUS\s+(WHITE|BRONZE|SILVER|GOLD|PLATINUM|TITANIUM)\b`)
+	towerPattern       = regexp.MustCompile(`(?i)\b(FULL|MID|MINI)[\s-]?TOWER\b`)
+)
+
+// parseAttributes extracts the key attributes of a product title for the
+// given category kind. Unknown kinds yield no attributes.
+func parseAttributes(kind, title string) map[string]string {
+	attrs := make(map[string]string)
+
+	switch strings.ToLower(kind) {
+	case kindGPU:
+		if m := gpuChipsetPattern.FindStringSubmatch(title); m != nil {
+			attrs["chipset"] = joinUpper(m[1], m[2], m[3])
+		}
+		if m := vramPattern.FindStringSubmatch(title); m != nil {
+			attrs["memory"] = m[1] + "GB"
+		}
+
+	case kindCPU:
+		if m := intelCPUPattern.FindStringSubmatch(title); m != nil {
+			attrs["model"] = strings.ToUpper(strings.Join(strings.Fields(m[1]), " ")) + "-" + strings.ToUpper(m[2])
+		} else if m := ryzenCPUPattern.FindStringSubmatch(title); m != nil {
+			attrs["model"] = "RYZEN " + m[1] + " " + strings.ToUpper(m[2])
+		}
+		setSocket(attrs, title)
+
+	case kindMotherboard:
+		setSocket(attrs, title)
+		if m := chipsetPattern.FindStringSubmatch(title); m != nil {
+			attrs["chipset"] = strings.ToUpper(m[1])
+		}
+		if m := formFactorPattern.FindStringSubmatch(title); m != nil {
+			attrs["form_factor"] = normalizeFormFactor(m[1])
+		}
+
+	case kindRAM:
+		if m := ramKitPattern.FindStringSubmatch(title); m != nil {
+			modules, _ := strconv.Atoi(m[1])
+			size, _ := strconv.Atoi(m[2])
+			attrs["capacity"] = strconv.Itoa(modules*size) + "GB"
+			attrs["modules"] = m[1]
+		} else if m := vramPattern.FindStringSubmatch(title); m != nil {
+			attrs["capacity"] = m[1] + "GB"
+		}
+		if m := speedPattern.FindStringSubmatch(title); m != nil {
+			attrs["speed"] = m[1] + "MHz"
+		}
+		if m := ramTypePattern.FindStringSubmatch(title); m != nil {
+			attrs["type"] = strings.ToUpper(m[1])
+		}
+
+	case kindSSD:
+		if m := storagePattern.FindStringSubmatch(title); m != nil {
+			attrs["capacity"] = strings.ReplaceAll(m[1], ",", ".") + strings.ToUpper(m[2])
+		}
+		switch {
+		case nvmePattern.MatchString(title):
+			attrs["interface"] = "NVME"
+		case sataPattern.MatchString(title):
+			attrs["interface"] = "SATA"
+		case m2Pattern.MatchString(title):
+			attrs["interface"] = "M.2"
+		}
+
+	case kindPSU:
+		if m := wattagePattern.FindStringSubmatch(title); m != nil {
+			attrs["wattage"] = m[1] + "W"
+		}
+		if m := certificatePattern.FindStringSubmatch(title); m != nil {
+			attrs["certification"] = "80 PLUS " + strings.ToUpper(m[1])
+		}
+
+	case kindCase:
+		if m := towerPattern.FindStringSubmatch(title); m != nil {
+			attrs["form_factor"] = strings.ToUpper(m[1]) + " TOWER"
+		} else if m := formFactorPattern.FindStringSubmatch(title); m != nil {
+			attrs["form_factor"] = normalizeFormFactor(m[1])
+		}
+	}
+
+	if len(attrs) == 0 {
+		return nil
+	}
+	return attrs
+}
+
+func setSocket(attrs map[string]string, title string) {
+	if m := socketPattern.FindStringSubmatch(title); m != nil {
+		socket := strings.ToUpper(m[1])
+		socket = strings.NewReplacer(" ", "", "-", "").Replace(socket)
+		attrs["socket"] = socket
+	}
+}
+
+func normalizeFormFactor(raw string) string {
+	f := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(raw))
+	switch f {
+	case "MICROATX", "MATX":
+		return "MATX"
+	case "MINIITX", "ITX":
+		return "ITX"
+	case "EATX":
+		return "E-ATX"
+	default:
+		return f
+	}
+}
+
+func joinUpper(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, strings.ToUpper(p))
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
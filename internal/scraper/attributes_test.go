@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAttributes(t *testing.T) {
+	tests := []struct {
+		kind  string
+		title string
+		want  map[string]string
+	}{
+		{
+			kind:  "gpu",
+			title: "Placa de Video ASUS Dual GeForce RTX 4060 Ti 8GB GDDR6",
+			want:  map[string]string{"chipset": "RTX 4060 TI", "memory": "8GB"},
+		},
+		{
+			kind:  "cpu",
+			title: "Processador Intel Core i7-14700K, 20-Core, LGA1700",
+			want:  map[string]string{"model": "I7-14700K", "socket": "LGA1700"},
+		},
+		{
+			kind:  "cpu",
+			title: "Processador AMD Ryzen 7 7800X3D, 8-Core, AM5",
+			want:  map[string]string{"model": "RYZEN 7 7800X3D", "socket": "AM5"},
+		},
+		{
+			kind:  "motherboard",
+			title: "Placa Mae Gigabyte B650M Gaming X AX, DDR5, Socket AM5, M-ATX",
+			want:  map[string]string{"socket": "AM5", "chipset": "B650", "form_factor": "MATX"},
+		},
+		{
+			kind:  "ram",
+			title: "Memoria Kingston Fury Beast, 16GB (2x8GB), 3200MHz, DDR4",
+			want:  map[string]string{"capacity": "16GB", "modules": "2", "speed": "3200MHz", "type": "DDR4"},
+		},
+		{
+			kind:  "ssd",
+			title: "SSD Kingston NV2 1TB M.2 2280 NVMe PCIe",
+			want:  map[string]string{"capacity": "1TB", "interface": "NVME"},
+		},
+		{
+			kind:  "psu",
+			title: "Fonte Corsair CV650 650W 80 Plus Bronze",
+			want:  map[string]string{"wattage": "650W", "certification": "80 PLUS BRONZE"},
+		},
+		{
+			kind:  "case",
+			title: "Gabinete Gamer Pichau Kazan 2, Mid-Tower, Vidro Temperado",
+			want:  map[string]string{"form_factor": "MID TOWER"},
+		},
+		{
+			kind:  "",
+			title: "Placa de Video RTX 4060",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.title, func(t *testing.T) {
+			got := parseAttributes(tt.kind, tt.title)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAttributes(%q) = %v, want %v", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractBrandMatchesWholeWords(t *testing.T) {
+	if got := extractBrandFromTitle("Placa Mae PCWare H610M LGA1700"); got != "OUTROS" {
+		t.Errorf("extractBrandFromTitle() = %q, want OUTROS", got)
+	}
+	if got := extractBrandFromTitle("Monitor LG UltraGear 24"); got != "LG" {
+		t.Errorf("extractBrandFromTitle() = %q, want LG", got)
+	}
+}
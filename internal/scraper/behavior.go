@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	behaviorNone      = "none"
+	behaviorLight     = "light"
+	behaviorRealistic = "realistic"
+)
+
+const (
+	maxScrollSteps = 40
+	hoverChance    = 0.15
+)
+
+const atBottomScript = `() => window.innerHeight + window.scrollY >= document.documentElement.scrollHeight - 2`
+
+// behavior simulates a visitor on a freshly loaded listing page.
+type behavior interface {
+	Name() string
+	Simulate(ctx context.Context, page playwright.Page) error
+}
+
+func newBehavior(name string, clock Clock, rng *rand.Rand) (behavior, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case behaviorNone:
+		return noBehavior{}, nil
+	case "", behaviorLight:
+		return lightBehavior{clock: clock, rng: rng}, nil
+	case behaviorRealistic:
+		return realisticBehavior{clock: clock, rng: rng}, nil
+	default:
+		return nil, fmt.Errorf("perfil de comportamento desconhecido: %q", name)
+	}
+}
+
+type noBehavior struct{}
+
+func (noBehavior) Name() string { return behaviorNone }
+
+func (noBehavior) Simulate(context.Context, playwright.Page) error { return nil }
+
+// lightBehavior does a single wheel scroll followed by a short pause.
+type lightBehavior struct {
+	clock Clock
+	rng   *rand.Rand
+}
+
+func (lightBehavior) Name() string { return behaviorLight }
+
+func (b lightBehavior) Simulate(ctx context.Context, page playwright.Page) error {
+	scrollAmount := float64(b.rng.Intn(500) + 300)
+	page.Mouse().Wheel(0, scrollAmount)
+	return b.clock.Sleep(ctx, time.Duration(b.rng.Intn(2000)+1000)*time.Millisecond)
+}
+
+// realisticBehavior scrolls progressively to the bottom so lazy-loaded cards
+// render, moves the mouse between scrolls, occasionally hovers a card and
+// dwells on the page for a log-normally distributed time.
+type realisticBehavior struct {
+	clock Clock
+	rng   *rand.Rand
+}
+
+func (realisticBehavior) Name() string { return behaviorRealistic }
+
+func (b realisticBehavior) Simulate(ctx context.Context, page playwright.Page) error {
+	width, height := viewportSize(page)
+
+	for step := 0; step < maxScrollSteps; step++ {
+		page.Mouse().Move(
+			float64(b.rng.Intn(width)),
+			float64(b.rng.Intn(height)),
+			playwright.MouseMoveOptions{Steps: playwright.Int(b.rng.Intn(15) + 5)},
+		)
+		page.Mouse().Wheel(0, float64(b.rng.Intn(400)+300))
+
+		if b.rng.Float64() < hoverChance {
+			hoverRandomCard(page, b.rng)
+		}
+
+		if err := b.clock.Sleep(ctx, dwellTime(b.rng, 400*time.Millisecond, 0.5)); err != nil {
+			return err
+		}
+
+		atBottom, err := page.Evaluate(atBottomScript)
+		if err != nil {
+			return fmt.Errorf("erro ao verificar rolagem: %w", err)
+		}
+		if done, _ := atBottom.(bool); done {
+			break
+		}
+	}
+
+	return b.clock.Sleep(ctx, dwellTime(b.rng, 2*time.Second, 0.6))
+}
+
+func hoverRandomCard(page playwright.Page, rng *rand.Rand) {
+	cards := page.Locator(cardSelector)
+	count, err := cards.Count()
+	if err != nil || count == 0 {
+		return
+	}
+	cards.Nth(rng.Intn(count)).Hover(playwright.LocatorHoverOptions{
+		Timeout: playwright.Float(2000),
+	})
+}
+
+func viewportSize(page playwright.Page) (int, int) {
+	if size := page.ViewportSize(); size != nil && size.Width > 0 && size.Height > 0 {
+		return size.Width, size.Height
+	}
+	return 1280, 720
+}
+
+// dwellTime samples a log-normal duration whose median is the given value;
+// sigma controls how heavy the tail of long pauses is. Samples are capped at
+// five times the median.
+func dwellTime(rng *rand.Rand, median time.Duration, sigma float64) time.Duration {
+	factor := math.Min(math.Exp(sigma*rng.NormFloat64()), 5)
+	return time.Duration(float64(median) * factor)
+}
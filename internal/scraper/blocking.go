@@ -0,0 +1,96 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
+)
+
+// Blocked requests never reach the network, so the savings metric uses a
+// typical transfer size per resource type instead of measured bytes.
+var estimatedResourceBytes = map[string]float64{
+	"image":      40 * 1024,
+	"media":      500 * 1024,
+	"font":       30 * 1024,
+	"script":     60 * 1024,
+	"stylesheet": 20 * 1024,
+	"xhr":        5 * 1024,
+	"fetch":      5 * 1024,
+}
+
+const defaultEstimatedBytes = 10 * 1024
+
+var lightweightArgs = []string{
+	"--disable-extensions",
+	"--disable-background-networking",
+	"--disable-component-update",
+	"--disable-default-apps",
+	"--disable-sync",
+	"--mute-audio",
+	"--no-first-run",
+}
+
+type requestBlocker struct {
+	resourceTypes map[string]bool
+	urlPatterns   []string
+}
+
+func newRequestBlocker(cfg *config.Config) *requestBlocker {
+	types := make(map[string]bool, len(cfg.BlockedResourceTypes))
+	for _, t := range cfg.BlockedResourceTypes {
+		types[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+
+	patterns := make([]string, 0, len(cfg.BlockedURLPatterns))
+	for _, p := range cfg.BlockedURLPatterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+
+	return &requestBlocker{
+		resourceTypes: types,
+		urlPatterns:   patterns,
+	}
+}
+
+// match reports whether a request should be blocked and the label used for
+// metrics: the resource type, or "tracker" for URL pattern matches.
+func (b *requestBlocker) match(resourceType, url string) (bool, string) {
+	if b.resourceTypes[resourceType] {
+		return true, resourceType
+	}
+
+	urlLower := strings.ToLower(url)
+	for _, pattern := range b.urlPatterns {
+		if strings.Contains(urlLower, pattern) {
+			return true, "tracker"
+		}
+	}
+
+	return false, ""
+}
+
+func (b *requestBlocker) install(browserContext playwright.BrowserContext) error {
+	return browserContext.Route("**/*", func(route playwright.Route) {
+		request := route.Request()
+		resourceType := request.ResourceType()
+
+		blocked, label := b.match(resourceType, request.URL())
+		if !blocked {
+			route.Continue()
+			return
+		}
+
+		size, ok := estimatedResourceBytes[resourceType]
+		if !ok {
+			size = defaultEstimatedBytes
+		}
+
+		metrics.BlockedRequests.WithLabelValues(label).Inc()
+		metrics.BlockedBytesEstimated.WithLabelValues(label).Add(size)
+		route.Abort("blockedbyclient")
+	})
+}
@@ -0,0 +1,32 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+func TestRequestBlockerMatch(t *testing.T) {
+	b := newRequestBlocker(config.NewDefault())
+
+	tests := []struct {
+		resourceType string
+		url          string
+		blocked      bool
+		label        string
+	}{
+		{"image", "https://media.pichau.com.br/produto.jpg", true, "image"},
+		{"font", "https://www.pichau.com.br/fonts/roboto.woff2", true, "font"},
+		{"script", "https://www.googletagmanager.com/gtm.js", true, "tracker"},
+		{"script", "https://www.pichau.com.br/static/js/main.js", false, ""},
+		{"document", "https://www.pichau.com.br/hardware/placa-de-video", false, ""},
+	}
+
+	for _, tt := range tests {
+		blocked, label := b.match(tt.resourceType, tt.url)
+		if blocked != tt.blocked || label != tt.label {
+			t.Errorf("match(%q, %q) = (%v, %q), want (%v, %q)",
+				tt.resourceType, tt.url, blocked, label, tt.blocked, tt.label)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package scraper
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
+)
+
+// pageSource hands out the page used for each navigation.
+type pageSource interface {
+	Page() (playwright.Page, error)
+	alive() bool
+	Close()
+}
+
+func openBrowserSession(cfg *config.Config, fingerprints *fingerprintPool) (pageSource, error) {
+	return newBrowserSession(cfg, fingerprints)
+}
+
+// browserSession owns the Playwright driver, browser, context and page, and
+// relaunches them when Chromium crashes or the page is due for recycling.
+type browserSession struct {
+	cfg          *config.Config
+	fingerprints *fingerprintPool
+	// driver starts Playwright and returns the configured engine; tests
+	// replace it with a fake.
+	driver func() (playwright.BrowserType, error)
+
+	pw      *playwright.Playwright
+	browser playwright.Browser
+	context playwright.BrowserContext
+	page    playwright.Page
+
+	navigations int
+	generation  atomic.Int64
+	dead        atomic.Bool
+	// markDead marks the current launch as dead. It is only read on the
+	// scraper's goroutine; event handlers capture their own copy.
+	markDead func()
+}
+
+func newBrowserSession(cfg *config.Config, fingerprints *fingerprintPool) (*browserSession, error) {
+	b := &browserSession{
+		cfg:          cfg,
+		fingerprints: fingerprints,
+	}
+	b.driver = b.startDriver
+	if err := b.start(); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *browserSession) startDriver() (playwright.BrowserType, error) {
+	pw, err := playwright.Run()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar playwright: %w", err)
+	}
+	b.pw = pw
+	return browserType(pw, b.cfg.BrowserEngine)
+}
+
+func (b *browserSession) start() error {
+	// Events from a previous launch must not mark the new one as dead.
+	gen := b.generation.Add(1)
+	markDead := func() {
+		if b.generation.Load() == gen {
+			b.dead.Store(true)
+		}
+	}
+	b.dead.Store(false)
+
+	engine := normalizeEngine(b.cfg.BrowserEngine)
+	launcher, err := b.driver()
+	if err != nil {
+		return err
+	}
+
+	// Command-line switches are Chromium-specific.
+	var args []string
+	if engine == engineChromium {
+		args = append(args, "--disable-blink-features=AutomationControlled")
+		if b.cfg.LightweightBrowser {
+			args = append(args, lightweightArgs...)
+		}
+	}
+
+	browser, err := launcher.Launch(playwright.BrowserTypeLaunchOptions{
+		Headless: playwright.Bool(b.cfg.Headless),
+		Args:     args,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao abrir navegador: %w", err)
+	}
+	b.browser = browser
+	browser.OnDisconnected(func(playwright.Browser) { markDead() })
+
+	profile := b.fingerprints.Next(b.cfg)
+	context, err := browser.NewContext(contextOptions(profile))
+	if err != nil {
+		return fmt.Errorf("erro ao criar contexto: %w", err)
+	}
+	b.context = context
+
+	if profile.Platform != "" {
+		if err := context.AddInitScript(platformScript(profile.Platform)); err != nil {
+			return fmt.Errorf("erro ao aplicar perfil de navegador: %w", err)
+		}
+	}
+
+	slog.Info("navegador iniciado",
+		"engine", engine,
+		"profile", profile.Name,
+	)
+	context.OnClose(func(playwright.BrowserContext) { markDead() })
+
+	if b.cfg.BlockResources {
+		if err := newRequestBlocker(b.cfg).install(context); err != nil {
+			return fmt.Errorf("erro ao configurar bloqueio de recursos: %w", err)
+		}
+	}
+
+	b.markDead = markDead
+	return b.newPage(markDead)
+}
+
+// newPage opens a page on the current context. markDead belongs to the
+// launch that owns the context, so a late crash of the page cannot mark a
+// later launch as dead.
+func (b *browserSession) newPage(markDead func()) error {
+	page, err := b.context.NewPage()
+	if err != nil {
+		return fmt.Errorf("erro ao criar página: %w", err)
+	}
+	page.OnCrash(func(playwright.Page) { markDead() })
+
+	b.page = page
+	b.navigations = 0
+	return nil
+}
+
+func (b *browserSession) alive() bool {
+	if b.dead.Load() || b.browser == nil || b.page == nil {
+		return false
+	}
+	return b.browser.IsConnected() && !b.page.IsClosed()
+}
+
+// Page returns a usable page, relaunching the browser if it died and opening
+// a fresh page once the current one reached the recycling threshold.
+func (b *browserSession) Page() (playwright.Page, error) {
+	if !b.alive() {
+		if err := b.restart(); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.cfg.PageRecycleEvery > 0 && b.navigations >= b.cfg.PageRecycleEvery {
+		slog.Info("reciclando página", "navigations", b.navigations)
+		metrics.BrowserRestarts.WithLabelValues("recycle").Inc()
+
+		b.page.Close()
+		if err := b.newPage(b.markDead); err != nil {
+			return nil, err
+		}
+	}
+
+	b.navigations++
+	return b.page, nil
+}
+
+func (b *browserSession) restart() error {
+	slog.Warn("navegador indisponível, reiniciando")
+	metrics.BrowserRestarts.WithLabelValues("crash").Inc()
+
+	b.Close()
+	if err := b.start(); err != nil {
+		return fmt.Errorf("erro ao reiniciar navegador: %w", err)
+	}
+	return nil
+}
+
+func (b *browserSession) Close() {
+	if b.browser != nil {
+		b.browser.Close()
+		b.browser = nil
+	}
+	if b.pw != nil {
+		b.pw.Stop()
+		b.pw = nil
+	}
+	b.context = nil
+	b.page = nil
+}
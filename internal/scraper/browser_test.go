@@ -0,0 +1,152 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+// fakeLauncher launches fake browsers and keeps each one it launched.
+type fakeLauncher struct {
+	playwright.BrowserType
+	browsers []*fakeBrowser
+}
+
+func (l *fakeLauncher) Launch(...playwright.BrowserTypeLaunchOptions) (playwright.Browser, error) {
+	b := &fakeBrowser{connected: true}
+	l.browsers = append(l.browsers, b)
+	return b, nil
+}
+
+type fakeBrowser struct {
+	playwright.Browser
+	connected      bool
+	onDisconnected func(playwright.Browser)
+	context        *fakeContext
+}
+
+func (b *fakeBrowser) OnDisconnected(fn func(playwright.Browser)) { b.onDisconnected = fn }
+
+func (b *fakeBrowser) NewContext(...playwright.BrowserNewContextOptions) (playwright.BrowserContext, error) {
+	b.context = &fakeContext{}
+	return b.context, nil
+}
+
+func (b *fakeBrowser) IsConnected() bool { return b.connected }
+
+func (b *fakeBrowser) Close(...playwright.BrowserCloseOptions) error {
+	b.connected = false
+	return nil
+}
+
+type fakeContext struct {
+	playwright.BrowserContext
+	pages []*crashablePage
+}
+
+func (c *fakeContext) NewPage() (playwright.Page, error) {
+	p := &crashablePage{}
+	c.pages = append(c.pages, p)
+	return p, nil
+}
+
+func (c *fakeContext) OnClose(func(playwright.BrowserContext)) {}
+
+func (c *fakeContext) AddInitScript(playwright.Script) error { return nil }
+
+// crashablePage records its crash handler so tests can fire it.
+type crashablePage struct {
+	playwright.Page
+	closed  bool
+	onCrash func(playwright.Page)
+}
+
+func (p *crashablePage) OnCrash(fn func(playwright.Page)) { p.onCrash = fn }
+
+func (p *crashablePage) IsClosed() bool { return p.closed }
+
+func (p *crashablePage) Close(...playwright.PageCloseOptions) error {
+	p.closed = true
+	return nil
+}
+
+func (p *crashablePage) crash() { p.onCrash(p) }
+
+func newFakeBrowserSession(t *testing.T, cfg *config.Config) (*browserSession, *fakeLauncher) {
+	t.Helper()
+	cfg.BlockResources = false
+
+	launcher := &fakeLauncher{}
+	b := &browserSession{
+		cfg:          cfg,
+		fingerprints: &fingerprintPool{},
+		driver:       func() (playwright.BrowserType, error) { return launcher, nil },
+	}
+	if err := b.start(); err != nil {
+		t.Fatalf("start() erro: %v", err)
+	}
+	return b, launcher
+}
+
+func TestBrowserSessionRecyclesPage(t *testing.T) {
+	cfg := config.NewDefault()
+	cfg.PageRecycleEvery = 2
+	b, launcher := newFakeBrowserSession(t, cfg)
+
+	first, _ := b.Page()
+	if again, _ := b.Page(); again != first {
+		t.Fatal("página trocada antes do limite de navegações")
+	}
+
+	recycled, err := b.Page()
+	if err != nil {
+		t.Fatalf("Page() erro: %v", err)
+	}
+	if recycled == first || !first.(*crashablePage).closed {
+		t.Error("página não reciclada após o limite de navegações")
+	}
+	if len(launcher.browsers) != 1 {
+		t.Errorf("navegadores abertos = %d, want 1", len(launcher.browsers))
+	}
+}
+
+func TestBrowserSessionRestartsAfterCrash(t *testing.T) {
+	b, launcher := newFakeBrowserSession(t, config.NewDefault())
+
+	crashed, _ := b.Page()
+	crashed.(*crashablePage).crash()
+	if b.alive() {
+		t.Fatal("sessão viva após crash da página")
+	}
+
+	page, err := b.Page()
+	if err != nil {
+		t.Fatalf("Page() erro: %v", err)
+	}
+	if page == crashed || len(launcher.browsers) != 2 {
+		t.Fatalf("navegador não reiniciado: %d lançamentos", len(launcher.browsers))
+	}
+	if launcher.browsers[0].connected {
+		t.Error("navegador antigo não foi fechado")
+	}
+
+	// Late events from the first launch must not kill the second.
+	crashed.(*crashablePage).crash()
+	launcher.browsers[0].onDisconnected(launcher.browsers[0])
+	if !b.alive() {
+		t.Error("evento tardio do navegador antigo derrubou o novo")
+	}
+}
+
+func TestBrowserSessionRestartsAfterDisconnect(t *testing.T) {
+	b, launcher := newFakeBrowserSession(t, config.NewDefault())
+
+	launcher.browsers[0].connected = false
+	if _, err := b.Page(); err != nil {
+		t.Fatalf("Page() erro: %v", err)
+	}
+	if len(launcher.browsers) != 2 || !b.alive() {
+		t.Errorf("navegador não reiniciado após desconexão: %d lançamentos", len(launcher.browsers))
+	}
+}
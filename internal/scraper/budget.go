@@ -0,0 +1,33 @@
+package scraper
+
+import (
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+// categoryBudget splits the time left in the run between the current
+// category and the pending ones. A category with a TimeBudget gets it,
+// capped by what is left; the others share evenly what the pending budgeted
+// categories do not reserve. Time a category leaves unused goes back to the
+// pool, since the split is recomputed as each category starts.
+func categoryBudget(remaining time.Duration, current config.CategoryConfig, pending []config.CategoryConfig) time.Duration {
+	if current.TimeBudget > 0 {
+		return min(current.TimeBudget, remaining)
+	}
+
+	shared, sharing := remaining, 1
+	for _, c := range pending {
+		if c.TimeBudget > 0 {
+			shared -= c.TimeBudget
+		} else {
+			sharing++
+		}
+	}
+
+	// Budgets adding up to more than is left: fall back to an even split.
+	if shared <= 0 {
+		return remaining / time.Duration(len(pending)+1)
+	}
+	return shared / time.Duration(sharing)
+}
@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+func TestCategoryBudget(t *testing.T) {
+	plain := config.CategoryConfig{Name: "CPU"}
+	budgeted := config.CategoryConfig{Name: "GPU", TimeBudget: 10 * time.Minute}
+
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		current   config.CategoryConfig
+		pending   []config.CategoryConfig
+		want      time.Duration
+	}{
+		{"even split", 30 * time.Minute, plain, []config.CategoryConfig{plain, plain}, 10 * time.Minute},
+		{"last category takes the rest", 7 * time.Minute, plain, nil, 7 * time.Minute},
+		{"own budget", 30 * time.Minute, budgeted, []config.CategoryConfig{plain}, 10 * time.Minute},
+		{"own budget capped by deadline", 4 * time.Minute, budgeted, nil, 4 * time.Minute},
+		{"pending budget reserved", 30 * time.Minute, plain, []config.CategoryConfig{budgeted, plain}, 10 * time.Minute},
+		{"overcommitted budgets", 12 * time.Minute, plain, []config.CategoryConfig{budgeted, budgeted}, 4 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := categoryBudget(tt.remaining, tt.current, tt.pending); got != tt.want {
+				t.Errorf("categoryBudget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeUsesCategoryMaxPages(t *testing.T) {
+	cfg := virtualConfig()
+	cfg.MaxPages = 5
+	cfg.Categories[0].MaxPages = 2
+	cfg.Categories[0].WaitTimeMin = time.Minute
+	cfg.Categories[0].WaitTimeMax = time.Minute
+
+	clock := &fakeClock{}
+	page := &fakePage{pages: 5, cardsPerPage: 1}
+	if _, err := newVirtualScraper(cfg, clock, page).Scrape(context.Background()); err != nil {
+		t.Fatalf("Scrape() erro: %v", err)
+	}
+
+	if len(page.visited) != 2 {
+		t.Errorf("páginas visitadas = %d, want 2", len(page.visited))
+	}
+	if clock.slept < 2*time.Minute {
+		t.Errorf("tempo virtual = %v, want >= 2m com a janela da categoria", clock.slept)
+	}
+}
+
+func TestScrapeKeepsProductsWhenCategoryBudgetRunsOut(t *testing.T) {
+	cfg := virtualConfig()
+	cfg.Categories[0].TimeBudget = 90 * time.Second
+	cfg.Categories[0].WaitTimeMin = time.Minute
+	cfg.Categories[0].WaitTimeMax = time.Minute
+	cfg.Categories = append(cfg.Categories, config.CategoryConfig{
+		Name: "CPU", URL: "https://www.pichau.com.br/hardware/processadores", Filter: "placa",
+	})
+
+	// The second one-minute wait outlasts the GPU budget in virtual time.
+	clock := &fakeClock{}
+	page := &fakePage{pages: 5, cardsPerPage: 2}
+
+	products, err := newVirtualScraper(cfg, clock, page).Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape() erro: %v", err)
+	}
+
+	if len(products) == 0 {
+		t.Error("produtos da categoria interrompida foram descartados")
+	}
+	var gpuPages int
+	var cpuVisited bool
+	for _, u := range page.visited {
+		if strings.Contains(u, "processadores") {
+			cpuVisited = true
+		} else {
+			gpuPages++
+		}
+	}
+	if gpuPages >= 5 {
+		t.Errorf("orçamento da categoria ignorado: %d páginas GPU", gpuPages)
+	}
+	if !cpuVisited {
+		t.Errorf("categoria seguinte não foi coletada: %v", page.visited)
+	}
+}
+
+func TestScrapeSplitsRunDeadlineOnClock(t *testing.T) {
+	cfg := virtualConfig()
+	cfg.Categories[0].WaitTimeMin = time.Minute
+	cfg.Categories[0].WaitTimeMax = time.Minute
+
+	// Ten real seconds of run deadline are ten virtual seconds of budget,
+	// however long the virtual waits are.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	page := &fakePage{pages: 5, cardsPerPage: 2}
+	if _, err := newVirtualScraper(cfg, clock, page).Scrape(ctx); err != nil {
+		t.Fatalf("Scrape() erro: %v", err)
+	}
+
+	if len(page.visited) != 1 {
+		t.Errorf("páginas visitadas = %d, want 1 antes do fim do orçamento", len(page.visited))
+	}
+}
@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+// Clock abstracts time so the scrape loop can run in virtual time. Sleep must
+// return early with ctx.Err() when the context is cancelled. WithTimeout
+// returns a context that expires with context.DeadlineExceeded once d has
+// passed on this clock.
+type Clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) error
+	WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d)
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type Option func(*PichauScraper)
+
+func WithClock(clock Clock) Option {
+	return func(s *PichauScraper) {
+		s.clock = clock
+	}
+}
+
+// WithRand sets the random source used for waits and behaviour simulation.
+// It is not safe for concurrent use, matching the single-goroutine scraper.
+func WithRand(rng *rand.Rand) Option {
+	return func(s *PichauScraper) {
+		s.rng = rng
+	}
+}
+
+// WithConfigSource makes the scraper pick up configuration changes between
+// pages: source is polled before each page and a different *config.Config
+// replaces the active one. Categories, targets, filters and delays take
+// effect immediately; search listings from the next category and browser
+// settings only on the next run.
+func WithConfigSource(source func() *config.Config) Option {
+	return func(s *PichauScraper) {
+		s.source = source
+	}
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+var (
+	openBoxPattern     = regexp.MustCompile(`(?i)\b(open[\s-]?box|caixa aberta)\b`)
+	refurbishedPattern = regexp.MustCompile(`(?i)\b(recondicionad[oa]|refurbished|remanufaturad[oa]|seminov[oa])\b`)
+	kitPattern         = regexp.MustCompile(`(?i)\b(kit|combo)\b`)
+	addOnPattern       = regexp.MustCompile(`(?i)\+\s*(cooler|water\s?cooler|jogo|game|brinde|mouse|teclado|headset|fone|ssd|mem[oó]ria|placa|processador|fonte|gabinete|monitor)`)
+)
+
+// classifyCondition tags a title as new, open-box or refurbished and reports
+// whether it is a bundle. RAM kits ("Kit 2x8GB") are single products.
+func classifyCondition(kind, title string) (string, bool) {
+	condition := domain.ConditionNew
+	switch {
+	case refurbishedPattern.MatchString(title):
+		condition = domain.ConditionRefurbished
+	case openBoxPattern.MatchString(title):
+		condition = domain.ConditionOpenBox
+	}
+
+	bundle := addOnPattern.MatchString(title)
+	if !bundle && strings.ToLower(kind) != kindRAM {
+		bundle = kitPattern.MatchString(title)
+	}
+
+	return condition, bundle
+}
+
+func conditionAllowed(category config.CategoryConfig, product domain.Product) error {
+	if product.Bundle && category.ExcludeBundles {
+		return ErrExcludedBundle
+	}
+
+	if len(category.Conditions) == 0 {
+		return nil
+	}
+	for _, c := range category.Conditions {
+		if strings.EqualFold(strings.TrimSpace(c), product.Condition) {
+			return nil
+		}
+	}
+	return ErrExcludedCondition
+}
@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+func TestClassifyCondition(t *testing.T) {
+	tests := []struct {
+		kind      string
+		title     string
+		condition string
+		bundle    bool
+	}{
+		{"gpu", "Placa de Video ASUS RTX 4060 8GB", domain.ConditionNew, false},
+		{"gpu", "Placa de Video MSI RTX 3060 Open Box", domain.ConditionOpenBox, false},
+		{"cpu", "Processador Intel i5-12400F Recondicionado", domain.ConditionRefurbished, false},
+		{"cpu", "Processador AMD Ryzen 5 5600 + Cooler Wraith Stealth", domain.ConditionNew, true},
+		{"cpu", "Kit Upgrade Ryzen 5 5600 Placa Mae A520M", domain.ConditionNew, true},
+		{"ram", "Memoria Kingston Fury Kit 16GB (2x8GB) DDR4", domain.ConditionNew, false},
+		{"motherboard", "Placa Mae ASUS B650M (Wi-Fi + Bluetooth)", domain.ConditionNew, false},
+	}
+
+	for _, tt := range tests {
+		condition, bundle := classifyCondition(tt.kind, tt.title)
+		if condition != tt.condition || bundle != tt.bundle {
+			t.Errorf("classifyCondition(%q) = (%q, %v), want (%q, %v)",
+				tt.title, condition, bundle, tt.condition, tt.bundle)
+		}
+	}
+}
+
+func TestParseCardAppliesConditionFilters(t *testing.T) {
+	category := config.CategoryConfig{
+		Name:           "GPU",
+		Filter:         "placa",
+		Kind:           "gpu",
+		Conditions:     []string{"new"},
+		ExcludeBundles: true,
+	}
+	s := NewPichauScraper(config.NewDefault())
+
+	_, _, err := s.parseCard(cardData{Title: "Placa de Video RTX 3060 Open Box", Price: "R$ 1.299,99"}, category, 1)
+	if !errors.Is(err, ErrExcludedCondition) {
+		t.Errorf("open box: erro = %v, want ErrExcludedCondition", err)
+	}
+
+	_, _, err = s.parseCard(cardData{Title: "Placa de Video RTX 4060 + Jogo", Price: "R$ 1.999,99"}, category, 1)
+	if !errors.Is(err, ErrExcludedBundle) {
+		t.Errorf("kit: erro = %v, want ErrExcludedBundle", err)
+	}
+
+	product, _, err := s.parseCard(cardData{Title: "Placa de Video RTX 4060", Price: "R$ 1.899,99"}, category, 1)
+	if err != nil || product.Condition != domain.ConditionNew || product.Bundle {
+		t.Errorf("produto novo rejeitado: %+v, err=%v", product, err)
+	}
+}
@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrOutsideTargets = errors.New("skip: fora dos alvos")
+	ErrEmptyFields    = errors.New("título ou preço vazio")
+	ErrFilterMismatch = errors.New("não corresponde ao filtro")
+	ErrInvalidPrice   = errors.New("preço inválido")
+
+	ErrUnparseablePrice = errors.New("preço não reconhecido")
+
+	ErrExcludedCondition = errors.New("condição excluída")
+	ErrExcludedBundle    = errors.New("kit excluído")
+)
+
+var skipReasons = []struct {
+	err    error
+	reason string
+}{
+	{ErrOutsideTargets, "outside_targets"},
+	{ErrEmptyFields, "empty_fields"},
+	{ErrFilterMismatch, "filter_mismatch"},
+	{ErrInvalidPrice, "invalid_price"},
+	{ErrUnparseablePrice, "unparseable_price"},
+	{ErrExcludedCondition, "excluded_condition"},
+	{ErrExcludedBundle, "excluded_bundle"},
+}
+
+// skipReason maps an extraction error to the label used in metrics and
+// summaries. Unknown errors are reported as "other".
+func skipReason(err error) string {
+	for _, r := range skipReasons {
+		if errors.Is(err, r.err) {
+			return r.reason
+		}
+	}
+	return "other"
+}
+
+// SkipSummary counts dropped cards per category and reason.
+type SkipSummary map[string]map[string]int
+
+func (s SkipSummary) add(category, reason string, n int) {
+	if n == 0 {
+		return
+	}
+	if s[category] == nil {
+		s[category] = make(map[string]int)
+	}
+	s[category][reason] += n
+}
+
+func (s SkipSummary) merge(category string, counts map[string]int) {
+	for reason, n := range counts {
+		s.add(category, reason, n)
+	}
+}
+
+func (s SkipSummary) Total(category string) int {
+	total := 0
+	for _, n := range s[category] {
+		total += n
+	}
+	return total
+}
+
+func (s SkipSummary) Categories() []string {
+	categories := make([]string, 0, len(s))
+	for category := range s {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+func (s SkipSummary) Reasons(category string) []string {
+	reasons := make([]string, 0, len(s[category]))
+	for reason := range s[category] {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
@@ -0,0 +1,199 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/metrics"
+)
+
+const cardSelector = ".MuiCard-root"
+
+// cardsScript collects every product card in a single round trip. It mirrors
+// the locator path: title from the first h2 (falling back to the first
+// .MuiTypography-root) and price from the innermost element matching /R\$/.
+// Struck-through prices are returned separately as the original price,
+// together with any "% OFF" badge, coupon hint and star rating (its label
+// plus the surrounding text, which carries the review count).
+const cardsScript = `(selector) => {
+	const text = (el) => (el && el.textContent) || "";
+	const pricePattern = /R\$/;
+	const discountPattern = /\d+\s*%\s*OFF/i;
+	const couponPattern = /cupom/i;
+
+	const struck = (el) => {
+		for (let node = el; node; node = node.parentElement) {
+			if (node.matches("s, del, strike")) {
+				return true;
+			}
+			if (getComputedStyle(node).textDecorationLine.includes("line-through")) {
+				return true;
+			}
+			if (node.matches(selector)) {
+				break;
+			}
+		}
+		return false;
+	};
+
+	const innermost = (card, pattern, accept) => {
+		for (const el of card.querySelectorAll("*")) {
+			if (!pattern.test(text(el))) {
+				continue;
+			}
+			const inner = Array.from(el.children).some((child) => pattern.test(text(child)));
+			if (!inner && accept(el)) {
+				return text(el);
+			}
+		}
+		return "";
+	};
+
+	const any = () => true;
+
+	const rating = (card) => {
+		const el = card.querySelector(".MuiRating-root, [aria-label*='estrela' i], [aria-label*='star' i]");
+		if (!el) {
+			return { label: "", context: "" };
+		}
+		return {
+			label: el.getAttribute("aria-label") || text(el),
+			context: text(el.parentElement),
+		};
+	};
+
+	return JSON.stringify(Array.from(document.querySelectorAll(selector), (card) => {
+		let title = text(card.querySelector("h2"));
+		if (title === "") {
+			title = text(card.querySelector(".MuiTypography-root"));
+		}
+		const stars = rating(card);
+		return {
+			title: title,
+			price: innermost(card, pricePattern, (el) => !struck(el)),
+			original_price: innermost(card, pricePattern, struck),
+			discount: innermost(card, discountPattern, any),
+			coupon: innermost(card, couponPattern, any),
+			rating: stars.label,
+			reviews: stars.context,
+		};
+	}));
+}`
+
+type cardData struct {
+	Title         string `json:"title"`
+	Price         string `json:"price"`
+	OriginalPrice string `json:"original_price"`
+	Discount      string `json:"discount"`
+	Coupon        string `json:"coupon"`
+	Rating        string `json:"rating"`
+	Reviews       string `json:"reviews"`
+}
+
+func collectCards(page playwright.Page) ([]cardData, error) {
+	result, err := page.Evaluate(cardsScript, cardSelector)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao avaliar cards: %w", err)
+	}
+
+	raw, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("resultado inesperado da avaliação: %T", result)
+	}
+
+	var cards []cardData
+	if err := json.Unmarshal([]byte(raw), &cards); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar cards: %w", err)
+	}
+
+	return cards, nil
+}
+
+// readCards prefers the single-evaluation path and falls back to per-card
+// locators if the evaluation fails.
+func readCards(page playwright.Page) ([]cardData, error) {
+	cards, err := collectCards(page)
+	if err == nil {
+		return cards, nil
+	}
+
+	slog.Warn("extração em lote falhou, usando locators", "error", err)
+	metrics.ExtractionFallbacks.Inc()
+	return collectCardsWithLocators(page)
+}
+
+// Selectors of the locator fallback. Without computed styles it only
+// recognises <s>, <del> and <strike> as struck-through prices.
+const (
+	priceSelector    = `text=/R\$/`
+	struckSelector   = "s, del, strike"
+	discountSelector = `text=/\d+\s*%\s*OFF/i`
+	couponSelector   = `text=/cupom/i`
+	ratingSelector   = ".MuiRating-root, [aria-label*='estrela' i], [aria-label*='star' i]"
+)
+
+// collectCardsWithLocators reads the same fields as cardsScript, one locator
+// call at a time.
+func collectCardsWithLocators(page playwright.Page) ([]cardData, error) {
+	items, err := page.Locator(cardSelector).All()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter itens: %w", err)
+	}
+
+	cards := make([]cardData, 0, len(items))
+	for _, item := range items {
+		cards = append(cards, readCardWithLocators(item))
+	}
+
+	return cards, nil
+}
+
+func readCardWithLocators(item playwright.Locator) cardData {
+	card := cardData{
+		Title:    firstText(item.Locator("h2")),
+		Discount: firstText(item.Locator(discountSelector)),
+		Coupon:   firstText(item.Locator(couponSelector)),
+	}
+	if card.Title == "" {
+		card.Title = firstText(item.Locator(".MuiTypography-root"))
+	}
+
+	struck, _ := item.Locator(struckSelector).AllTextContents()
+	for _, text := range struck {
+		if strings.Contains(text, "R$") {
+			card.OriginalPrice = text
+			break
+		}
+	}
+	prices, _ := item.Locator(priceSelector).AllTextContents()
+	for _, text := range prices {
+		if text != card.OriginalPrice {
+			card.Price = text
+			break
+		}
+	}
+
+	rating := item.Locator(ratingSelector).First()
+	if n, _ := rating.Count(); n > 0 {
+		card.Rating, _ = rating.GetAttribute("aria-label")
+		if card.Rating == "" {
+			card.Rating, _ = rating.TextContent()
+		}
+		card.Reviews, _ = rating.Locator("xpath=..").TextContent()
+	}
+
+	return card
+}
+
+// firstText returns the text of the first element matched, or "" without
+// waiting when there is none.
+func firstText(l playwright.Locator) string {
+	texts, err := l.AllTextContents()
+	if err != nil || len(texts) == 0 {
+		return ""
+	}
+	return texts[0]
+}
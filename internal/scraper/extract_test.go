@@ -0,0 +1,265 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const benchmarkCards = 36
+
+func listingFixture(cards int) string {
+	var b strings.Builder
+	b.WriteString("<html><body>")
+	for i := 0; i < cards; i++ {
+		fmt.Fprintf(&b, `<div class="MuiCard-root">
+			<a><h2 class="MuiTypography-root">Placa de Video ASUS RTX 4060 Modelo %d</h2></a>
+			<div><span>à vista</span><div><span>R$ %d.%03d,99</span></div></div>
+			<div>ou 12x de R$ 199,99</div>
+		</div>`, i, 1+i%9, i)
+	}
+	b.WriteString("</body></html>")
+	return b.String()
+}
+
+func newFixturePage(tb testing.TB, html string) playwright.Page {
+	tb.Helper()
+
+	pw, err := playwright.Run()
+	if err != nil {
+		tb.Skipf("playwright indisponível: %v", err)
+	}
+	tb.Cleanup(func() { pw.Stop() })
+
+	browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
+		Headless: playwright.Bool(true),
+	})
+	if err != nil {
+		tb.Skipf("chromium indisponível: %v", err)
+	}
+	tb.Cleanup(func() { browser.Close() })
+
+	page, err := browser.NewPage()
+	if err != nil {
+		tb.Fatalf("erro ao criar página: %v", err)
+	}
+
+	if err := page.SetContent(html); err != nil {
+		tb.Fatalf("erro ao carregar fixture: %v", err)
+	}
+
+	return page
+}
+
+func TestCollectCardsMatchesLocators(t *testing.T) {
+	page := newFixturePage(t, listingFixture(5))
+
+	bulk, err := collectCards(page)
+	if err != nil {
+		t.Fatalf("collectCards() erro: %v", err)
+	}
+
+	located, err := collectCardsWithLocators(page)
+	if err != nil {
+		t.Fatalf("collectCardsWithLocators() erro: %v", err)
+	}
+
+	if len(bulk) != len(located) {
+		t.Fatalf("quantidade divergente: bulk=%d locators=%d", len(bulk), len(located))
+	}
+
+	for i := range bulk {
+		if bulk[i] != located[i] {
+			t.Errorf("card %d: bulk=%+v locators=%+v", i, bulk[i], located[i])
+		}
+	}
+}
+
+// locator lets fakeLocator embed the interface it overrides Locator of.
+type locator = playwright.Locator
+
+// fakeLocator answers the locator calls of the fallback path from canned
+// texts; children maps relative selectors to their matches.
+type fakeLocator struct {
+	locator
+	items    []playwright.Locator
+	texts    []string
+	attrs    map[string]string
+	children map[string]*fakeLocator
+	allErr   error
+}
+
+func (l *fakeLocator) All() ([]playwright.Locator, error) { return l.items, l.allErr }
+
+func (l *fakeLocator) AllTextContents() ([]string, error) { return l.texts, nil }
+
+func (l *fakeLocator) First() playwright.Locator { return l }
+
+func (l *fakeLocator) Count() (int, error) { return len(l.texts), nil }
+
+func (l *fakeLocator) TextContent(...playwright.LocatorTextContentOptions) (string, error) {
+	if len(l.texts) == 0 {
+		return "", errors.New("timeout")
+	}
+	return l.texts[0], nil
+}
+
+func (l *fakeLocator) GetAttribute(name string, _ ...playwright.LocatorGetAttributeOptions) (string, error) {
+	return l.attrs[name], nil
+}
+
+func (l *fakeLocator) Locator(selector interface{}, _ ...playwright.LocatorLocatorOptions) playwright.Locator {
+	if child, ok := l.children[selector.(string)]; ok {
+		return child
+	}
+	return &fakeLocator{}
+}
+
+// evalPage returns a canned evaluation result and serves cards to the
+// locator fallback.
+type evalPage struct {
+	playwright.Page
+	result interface{}
+	err    error
+	cards  []playwright.Locator
+}
+
+func (p *evalPage) Evaluate(string, ...interface{}) (interface{}, error) { return p.result, p.err }
+
+func (p *evalPage) Locator(selector string, _ ...playwright.PageLocatorOptions) playwright.Locator {
+	if selector != cardSelector {
+		return &fakeLocator{}
+	}
+	return &fakeLocator{items: p.cards}
+}
+
+func texts(values ...string) *fakeLocator {
+	return &fakeLocator{texts: values}
+}
+
+func TestReadCardsDecodesEvaluation(t *testing.T) {
+	page := &evalPage{result: `[{"title":"Placa de Video RTX 4060","price":"R$ 1.899,90",` +
+		`"original_price":"R$ 2.199,90","discount":"14% OFF","coupon":"Cupom PICHAU10",` +
+		`"rating":"4.5 estrelas","reviews":"4.5 (12)"}]`}
+
+	cards, err := readCards(page)
+	if err != nil {
+		t.Fatalf("readCards() erro: %v", err)
+	}
+	want := cardData{
+		Title: "Placa de Video RTX 4060", Price: "R$ 1.899,90", OriginalPrice: "R$ 2.199,90",
+		Discount: "14% OFF", Coupon: "Cupom PICHAU10", Rating: "4.5 estrelas", Reviews: "4.5 (12)",
+	}
+	if len(cards) != 1 || cards[0] != want {
+		t.Errorf("readCards() = %+v, want [%+v]", cards, want)
+	}
+}
+
+func TestCollectCardsRejectsUnexpectedResult(t *testing.T) {
+	for name, result := range map[string]interface{}{
+		"não é texto":   map[string]interface{}{"title": "x"},
+		"json inválido": "[{",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := collectCards(&evalPage{result: result}); err == nil {
+				t.Errorf("collectCards(%v) sem erro", result)
+			}
+		})
+	}
+}
+
+func TestReadCardsFallsBackToLocators(t *testing.T) {
+	promoted := &fakeLocator{children: map[string]*fakeLocator{
+		"h2":             texts("Placa de Video RTX 4060"),
+		priceSelector:    texts("R$ 2.199,90", "R$ 1.899,90", "ou 12x de R$ 158,32"),
+		struckSelector:   texts("R$ 2.199,90"),
+		discountSelector: texts("14% OFF"),
+		couponSelector:   texts("Cupom PICHAU10"),
+		ratingSelector: {
+			texts:    []string{"★★★★☆"},
+			attrs:    map[string]string{"aria-label": "4.5 estrelas"},
+			children: map[string]*fakeLocator{"xpath=..": texts("4.5 (12)")},
+		},
+	}}
+	plain := &fakeLocator{children: map[string]*fakeLocator{
+		".MuiTypography-root": texts("Processador Ryzen 5 5600"),
+		priceSelector:         texts("R$ 699,00"),
+	}}
+	page := &evalPage{err: errors.New("execution context was destroyed"), cards: []playwright.Locator{promoted, plain}}
+
+	cards, err := readCards(page)
+	if err != nil {
+		t.Fatalf("readCards() erro: %v", err)
+	}
+
+	want := []cardData{
+		{
+			Title: "Placa de Video RTX 4060", Price: "R$ 1.899,90", OriginalPrice: "R$ 2.199,90",
+			Discount: "14% OFF", Coupon: "Cupom PICHAU10", Rating: "4.5 estrelas", Reviews: "4.5 (12)",
+		},
+		{Title: "Processador Ryzen 5 5600", Price: "R$ 699,00"},
+	}
+	if len(cards) != len(want) {
+		t.Fatalf("readCards() = %+v, want %+v", cards, want)
+	}
+	for i := range want {
+		if cards[i] != want[i] {
+			t.Errorf("card %d = %+v, want %+v", i, cards[i], want[i])
+		}
+	}
+}
+
+// collectCardsPerLocator reproduces the extraction that predates the single
+// evaluation: one locator round trip per field of each card, reading only
+// the title and price. It is the baseline BenchmarkExtractPage measures
+// collectCards against.
+func collectCardsPerLocator(page playwright.Page) ([]cardData, error) {
+	items, err := page.Locator(cardSelector).All()
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]cardData, 0, len(items))
+	for _, item := range items {
+		title, _ := item.Locator("h2").First().TextContent()
+		if title == "" {
+			title, _ = item.Locator(".MuiTypography-root").First().TextContent()
+		}
+		price, _ := item.Locator("text=/R\\$/").First().TextContent()
+		cards = append(cards, cardData{Title: title, Price: price})
+	}
+	return cards, nil
+}
+
+func BenchmarkExtractPage(b *testing.B) {
+	page := newFixturePage(b, listingFixture(benchmarkCards))
+
+	b.Run("per-card-locators", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := collectCardsPerLocator(page); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("evaluate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := collectCards(page); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// The fallback reads every field, with more round trips per card than
+	// the baseline; it only runs when the evaluation fails.
+	b.Run("fallback", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := collectCardsWithLocators(page); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
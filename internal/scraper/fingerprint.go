@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+const (
+	engineChromium = "chromium"
+	engineFirefox  = "firefox"
+	engineWebKit   = "webkit"
+)
+
+func browserType(pw *playwright.Playwright, engine string) (playwright.BrowserType, error) {
+	switch normalizeEngine(engine) {
+	case engineChromium:
+		return pw.Chromium, nil
+	case engineFirefox:
+		return pw.Firefox, nil
+	case engineWebKit:
+		return pw.WebKit, nil
+	default:
+		return nil, fmt.Errorf("engine de navegador desconhecida: %q", engine)
+	}
+}
+
+func normalizeEngine(engine string) string {
+	engine = strings.ToLower(strings.TrimSpace(engine))
+	if engine == "" {
+		return engineChromium
+	}
+	return engine
+}
+
+// fingerprintPool hands out the profiles matching the configured engine in
+// round-robin order, one per browser context. It belongs to the scraper, so
+// the rotation carries on across runs and browser relaunches, and starts at
+// a random profile so separate processes do not all open with the first.
+type fingerprintPool struct {
+	next int
+}
+
+func newFingerprintPool(rng *rand.Rand) *fingerprintPool {
+	return &fingerprintPool{next: rng.Intn(1 << 16)}
+}
+
+// Next returns the next profile for cfg's engine, falling back to one built
+// from cfg.UserAgent when none is configured.
+func (p *fingerprintPool) Next(cfg *config.Config) config.FingerprintProfile {
+	profiles := engineProfiles(cfg)
+	profile := profiles[p.next%len(profiles)]
+	p.next++
+	return profile
+}
+
+func engineProfiles(cfg *config.Config) []config.FingerprintProfile {
+	engine := normalizeEngine(cfg.BrowserEngine)
+
+	var profiles []config.FingerprintProfile
+	for _, p := range cfg.Fingerprints {
+		if normalizeEngine(p.Engine) == engine {
+			profiles = append(profiles, p)
+		}
+	}
+
+	if len(profiles) == 0 {
+		profiles = append(profiles, config.FingerprintProfile{
+			Name:      "default",
+			Engine:    engine,
+			UserAgent: cfg.UserAgent,
+		})
+	}
+	return profiles
+}
+
+func contextOptions(profile config.FingerprintProfile) playwright.BrowserNewContextOptions {
+	opts := playwright.BrowserNewContextOptions{}
+
+	if profile.UserAgent != "" {
+		opts.UserAgent = playwright.String(profile.UserAgent)
+	}
+	if profile.ViewportWidth > 0 && profile.ViewportHeight > 0 {
+		size := &playwright.Size{Width: profile.ViewportWidth, Height: profile.ViewportHeight}
+		opts.Viewport = size
+		opts.Screen = size
+	}
+	if profile.Locale != "" {
+		opts.Locale = playwright.String(profile.Locale)
+		opts.ExtraHttpHeaders = map[string]string{
+			"Accept-Language": acceptLanguage(profile.Locale),
+		}
+	}
+	if profile.TimezoneID != "" {
+		opts.TimezoneId = playwright.String(profile.TimezoneID)
+	}
+
+	return opts
+}
+
+func acceptLanguage(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	if lang == locale {
+		return locale
+	}
+	return locale + "," + lang + ";q=0.9"
+}
+
+// platformScript overrides navigator.platform so it agrees with the user agent.
+func platformScript(platform string) playwright.Script {
+	content := "Object.defineProperty(Navigator.prototype, 'platform', { get: () => " +
+		strconv.Quote(platform) + " });"
+	return playwright.Script{Content: playwright.String(content)}
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+func TestFingerprintPoolRotatesProfilesOfEngine(t *testing.T) {
+	cfg := config.NewDefault()
+	cfg.BrowserEngine = "firefox"
+
+	pool := &fingerprintPool{}
+	first := pool.Next(cfg)
+	second := pool.Next(cfg)
+	third := pool.Next(cfg)
+
+	if first.Name == second.Name {
+		t.Errorf("perfis não rotacionaram: %q", first.Name)
+	}
+	if third.Name != first.Name {
+		t.Errorf("rotação deveria voltar ao início: got %q, want %q", third.Name, first.Name)
+	}
+
+	for _, p := range []config.FingerprintProfile{first, second} {
+		if p.Engine != "firefox" || !strings.Contains(p.UserAgent, "Firefox") {
+			t.Errorf("perfil %q inconsistente com a engine firefox: %q", p.Name, p.UserAgent)
+		}
+	}
+}
+
+func TestFingerprintPoolFallsBackToUserAgent(t *testing.T) {
+	cfg := config.NewDefault()
+	cfg.Fingerprints = nil
+
+	profile := (&fingerprintPool{}).Next(cfg)
+	if profile.UserAgent != cfg.UserAgent {
+		t.Errorf("UserAgent = %q, want %q", profile.UserAgent, cfg.UserAgent)
+	}
+}
+
+func TestFingerprintPoolStartsAtSeededProfile(t *testing.T) {
+	cfg := config.NewDefault()
+
+	starts := make(map[string]bool)
+	for seed := int64(0); seed < 20; seed++ {
+		starts[newFingerprintPool(rand.New(rand.NewSource(seed))).Next(cfg).Name] = true
+	}
+	if len(starts) < 2 {
+		t.Errorf("todas as sementes começaram pelo mesmo perfil: %v", starts)
+	}
+}
+
+func TestScrapeRotatesProfileAcrossRuns(t *testing.T) {
+	cfg := virtualConfig()
+	page := &fakePage{pages: 1, cardsPerPage: 1}
+	s := newVirtualScraper(cfg, &fakeClock{}, page)
+
+	var profiles []string
+	s.newSession = func(cfg *config.Config, fingerprints *fingerprintPool) (pageSource, error) {
+		profiles = append(profiles, fingerprints.Next(cfg).Name)
+		return &fakeSession{page: page}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.Scrape(context.Background()); err != nil {
+			t.Fatalf("Scrape() erro: %v", err)
+		}
+	}
+	if len(profiles) != 2 || profiles[0] == profiles[1] {
+		t.Errorf("perfis por execução = %v, want dois perfis diferentes", profiles)
+	}
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+// observationState is the last known price of every product per category,
+// persisted between runs so incremental runs can tell unchanged pages apart.
+type observationState struct {
+	LastFullRun  time.Time                          `json:"last_full_run"`
+	Observations map[string]map[string]domain.Money `json:"observations"`
+}
+
+func loadObservationState(path string) (*observationState, error) {
+	state := &observationState{Observations: make(map[string]map[string]domain.Money)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler estado incremental: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar estado incremental: %w", err)
+	}
+	if state.Observations == nil {
+		state.Observations = make(map[string]map[string]domain.Money)
+	}
+
+	return state, nil
+}
+
+func (st *observationState) save(path string) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar estado incremental: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de estado: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar estado incremental: %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// record stores the page's products and reports whether any of them is new
+// or changed price since the last observation.
+func (st *observationState) record(category string, products []domain.Product) bool {
+	known := st.Observations[category]
+	if known == nil {
+		known = make(map[string]domain.Money)
+		st.Observations[category] = known
+	}
+
+	changed := false
+	for _, p := range products {
+		if last, ok := known[p.Title]; !ok || last != p.Price {
+			changed = true
+		}
+		known[p.Title] = p.Price
+	}
+
+	return changed
+}
+
+// fullRunDue reports whether coverage requires walking every page this run.
+func (st *observationState) fullRunDue(now time.Time, interval time.Duration) bool {
+	return st.LastFullRun.IsZero() || now.Sub(st.LastFullRun) >= interval
+}
@@ -0,0 +1,92 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIncrementalStopsOnUnchangedPages(t *testing.T) {
+	cfg := virtualConfig()
+	cfg.Incremental = true
+	cfg.StateFile = filepath.Join(t.TempDir(), "observations.json")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// First run is a full run and records every page.
+	first := &fakePage{pages: 5, cardsPerPage: 2}
+	if _, err := newVirtualScraper(cfg, &fakeClock{now: now}, first).Scrape(context.Background()); err != nil {
+		t.Fatalf("primeira execução: %v", err)
+	}
+	if len(first.visited) != 5 {
+		t.Fatalf("execução completa visitou %d páginas, want 5", len(first.visited))
+	}
+
+	// Same listing an hour later: stops after IncrementalStopAfter pages.
+	second := &fakePage{pages: 5, cardsPerPage: 2}
+	if _, err := newVirtualScraper(cfg, &fakeClock{now: now.Add(time.Hour)}, second).Scrape(context.Background()); err != nil {
+		t.Fatalf("segunda execução: %v", err)
+	}
+	if len(second.visited) != cfg.IncrementalStopAfter {
+		t.Errorf("execução incremental visitou %d páginas, want %d", len(second.visited), cfg.IncrementalStopAfter)
+	}
+
+	// Once the interval elapses the run walks every page again.
+	third := &fakePage{pages: 5, cardsPerPage: 2}
+	later := now.Add(cfg.FullRunInterval + time.Minute)
+	if _, err := newVirtualScraper(cfg, &fakeClock{now: later}, third).Scrape(context.Background()); err != nil {
+		t.Fatalf("terceira execução: %v", err)
+	}
+	if len(third.visited) != 5 {
+		t.Errorf("execução completa periódica visitou %d páginas, want 5", len(third.visited))
+	}
+}
+
+func TestFailedFullRunIsRepeated(t *testing.T) {
+	cfg := virtualConfig()
+	cfg.Incremental = true
+	cfg.StateFile = filepath.Join(t.TempDir(), "observations.json")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Page 3 fails, so pages 3-5 were never observed.
+	first := &fakePage{pages: 5, cardsPerPage: 2}
+	first.onGoto = func(url string) error {
+		if strings.Contains(url, "page=3") {
+			return errors.New("net::ERR_CONNECTION_RESET")
+		}
+		return nil
+	}
+	if _, err := newVirtualScraper(cfg, &fakeClock{now: now}, first).Scrape(context.Background()); err != nil {
+		t.Fatalf("primeira execução: %v", err)
+	}
+
+	second := &fakePage{pages: 5, cardsPerPage: 2}
+	if _, err := newVirtualScraper(cfg, &fakeClock{now: now.Add(time.Hour)}, second).Scrape(context.Background()); err != nil {
+		t.Fatalf("segunda execução: %v", err)
+	}
+	if len(second.visited) != 5 {
+		t.Errorf("execução após falha visitou %d páginas, want 5 (execução completa)", len(second.visited))
+	}
+}
+
+func TestFullRunWithUnreadablePageIsRepeated(t *testing.T) {
+	cfg := virtualConfig()
+	cfg.Incremental = true
+	cfg.StateFile = filepath.Join(t.TempDir(), "observations.json")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	first := &fakePage{pages: 5, cardsPerPage: 2, unreadable: map[int]bool{3: true}}
+	if _, err := newVirtualScraper(cfg, &fakeClock{now: now}, first).Scrape(context.Background()); err != nil {
+		t.Fatalf("primeira execução: %v", err)
+	}
+
+	second := &fakePage{pages: 5, cardsPerPage: 2}
+	if _, err := newVirtualScraper(cfg, &fakeClock{now: now.Add(time.Hour)}, second).Scrape(context.Background()); err != nil {
+		t.Fatalf("segunda execução: %v", err)
+	}
+	if len(second.visited) != 5 {
+		t.Errorf("execução após página ilegível visitou %d páginas, want 5 (execução completa)", len(second.visited))
+	}
+}
@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+const (
+	searchQueryPlaceholder = "{query}"
+	defaultPageParam       = "page"
+)
+
+// listing is a paged set of results: either a category page or a store
+// search for a single target.
+type listing struct {
+	label     string
+	baseURL   string
+	pageParam string
+	params    url.Values
+}
+
+// categoryListings returns one search listing per target when the category
+// is in search mode and the store defines a search URL, otherwise the
+// category listing itself. Sort, facets and page size apply to both.
+func categoryListings(store config.StoreConfig, category config.CategoryConfig) ([]listing, error) {
+	params := listingParams(store, category)
+	pageParam := store.PageParam
+	if pageParam == "" {
+		pageParam = defaultPageParam
+	}
+
+	if !category.SearchTargets || len(category.Targets) == 0 {
+		return []listing{{
+			label:     "category",
+			baseURL:   category.URL,
+			pageParam: pageParam,
+			params:    params,
+		}}, nil
+	}
+
+	if store.SearchURL == "" {
+		return nil, fmt.Errorf("loja %q não define URL de busca", store.Name)
+	}
+
+	var listings []listing
+	queried := make(map[string]bool)
+	for _, target := range category.Targets {
+		query := strings.TrimSpace(target)
+		key := strings.ToLower(query)
+		if query == "" || queried[key] {
+			continue
+		}
+		queried[key] = true
+
+		listings = append(listings, listing{
+			label:     "search:" + query,
+			baseURL:   strings.ReplaceAll(store.SearchURL, searchQueryPlaceholder, url.QueryEscape(query)),
+			pageParam: pageParam,
+			params:    params,
+		})
+	}
+
+	return listings, nil
+}
+
+func listingParams(store config.StoreConfig, category config.CategoryConfig) url.Values {
+	params := url.Values{}
+
+	if category.Sort != "" && store.SortParam != "" {
+		params.Set(store.SortParam, category.Sort)
+	}
+	if category.PageSize > 0 && store.PageSizeParam != "" {
+		params.Set(store.PageSizeParam, strconv.Itoa(category.PageSize))
+	}
+	for name, values := range category.Facets {
+		for _, v := range values {
+			params.Add(name, v)
+		}
+	}
+
+	return params
+}
+
+// pageURL composes the listing URL for a page. Parameters already present in
+// the base URL are kept unless the listing sets them explicitly.
+func (l listing) pageURL(pageNum int) (string, error) {
+	u, err := url.Parse(l.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("URL inválida %q: %w", l.baseURL, err)
+	}
+
+	q := u.Query()
+	for name, values := range l.params {
+		q[name] = append([]string(nil), values...)
+	}
+	q.Set(l.pageParam, strconv.Itoa(pageNum))
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vitor-labes/pc-scraper/internal/config"
+)
+
+func TestCategoryListings(t *testing.T) {
+	store := config.StoreConfig{Name: "pichau", SearchURL: "https://www.pichau.com.br/search?q={query}"}
+	category := config.CategoryConfig{
+		Name:          "GPU",
+		URL:           "https://www.pichau.com.br/hardware/placa-de-video",
+		Targets:       []string{"RTX 4060", " rtx 4060", "RX 7600 XT"},
+		SearchTargets: true,
+	}
+
+	listings, err := categoryListings(store, category)
+	if err != nil {
+		t.Fatalf("categoryListings() erro: %v", err)
+	}
+	if len(listings) != 2 {
+		t.Fatalf("len(listings) = %d, want 2 (alvos repetidos devem ser ignorados)", len(listings))
+	}
+
+	got, err := listings[1].pageURL(2)
+	if err != nil {
+		t.Fatalf("pageURL() erro: %v", err)
+	}
+	if want := "https://www.pichau.com.br/search?page=2&q=RX+7600+XT"; got != want {
+		t.Errorf("pageURL() = %q, want %q", got, want)
+	}
+
+	category.SearchTargets = false
+	listings, _ = categoryListings(store, category)
+	if len(listings) != 1 || listings[0].baseURL != category.URL {
+		t.Errorf("modo categoria deveria usar a URL da categoria: %+v", listings)
+	}
+}
+
+func TestListingURLWithSortAndFacets(t *testing.T) {
+	store := config.StoreConfig{
+		Name:          "pichau",
+		PageParam:     "page",
+		SortParam:     "sort",
+		PageSizeParam: "limit",
+	}
+	category := config.CategoryConfig{
+		Name:     "GPU",
+		URL:      "https://www.pichau.com.br/hardware/placa-de-video?origem=menu",
+		Sort:     "price_asc",
+		PageSize: 48,
+		Facets: map[string][]string{
+			"marca": {"ASUS", "Gigabyte & Co"},
+		},
+	}
+
+	listings, err := categoryListings(store, category)
+	if err != nil {
+		t.Fatalf("categoryListings() erro: %v", err)
+	}
+
+	got, err := listings[0].pageURL(1)
+	if err != nil {
+		t.Fatalf("pageURL() erro: %v", err)
+	}
+
+	want := "https://www.pichau.com.br/hardware/placa-de-video?limit=48&marca=ASUS&marca=Gigabyte+%26+Co&origem=menu&page=1&sort=price_asc"
+	if got != want {
+		t.Errorf("pageURL() = %q, want %q", got, want)
+	}
+}
+
+func TestSearchModeDeduplicatesAcrossTargets(t *testing.T) {
+	cfg := virtualConfig()
+	cfg.Categories[0].Targets = []string{"RTX 4060", "4060"}
+	cfg.Categories[0].SearchTargets = true
+
+	page := &fakePage{pages: 2, cardsPerPage: 3}
+	products, err := newVirtualScraper(cfg, &fakeClock{}, page).Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape() erro: %v", err)
+	}
+
+	// Both queries return the same cards; each search stops at its first empty page.
+	if len(page.visited) != 6 {
+		t.Errorf("páginas visitadas = %d, want 6", len(page.visited))
+	}
+	if len(products) != 6 {
+		t.Errorf("len(products) = %d, want 6", len(products))
+	}
+}
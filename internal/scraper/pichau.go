@@ -2,10 +2,11 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/rand"
-	"strconv"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,16 +16,47 @@ import (
 	"github.com/vitor-labes/pc-scraper/internal/metrics"
 )
 
+// StoreName identifies Pichau in published messages and the config.
+const StoreName = "pichau"
+
 type PichauScraper struct {
-	cfg  *config.Config
-	seen map[string]bool
+	cfg      *config.Config
+	seen     map[string]bool
+	skips    SkipSummary
+	behavior behavior
+	clock    Clock
+	rng      *rand.Rand
+
+	state       *observationState
+	incremental bool
+	// complete stays true while every category and page of the run
+	// succeeds; only a complete full run resets the full-run interval.
+	complete bool
+
+	fingerprints *fingerprintPool
+	newSession   func(*config.Config, *fingerprintPool) (pageSource, error)
+	source       func() *config.Config
 }
 
-func NewPichauScraper(cfg *config.Config) *PichauScraper {
-	return &PichauScraper{
-		cfg:  cfg,
-		seen: make(map[string]bool),
+func NewPichauScraper(cfg *config.Config, opts ...Option) *PichauScraper {
+	s := &PichauScraper{
+		cfg:        cfg,
+		seen:       make(map[string]bool),
+		skips:      make(SkipSummary),
+		clock:      realClock{},
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		newSession: openBrowserSession,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.fingerprints = newFingerprintPool(s.rng)
+	return s
+}
+
+// Skips returns the dropped-card counts of the last Scrape call.
+func (s *PichauScraper) Skips() SkipSummary {
+	return s.skips
 }
 
 func ScrapePichau(ctx context.Context, cfg *config.Config) ([]domain.Product, error) {
@@ -33,55 +65,89 @@ func ScrapePichau(ctx context.Context, cfg *config.Config) ([]domain.Product, er
 }
 
 func (s *PichauScraper) Scrape(ctx context.Context) ([]domain.Product, error) {
-	pw, err := playwright.Run()
+	behavior, err := newBehavior(s.cfg.Store(StoreName).Behavior, s.clock, s.rng)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao iniciar playwright: %w", err)
+		return nil, err
 	}
-	defer pw.Stop()
+	s.behavior = behavior
 
-	browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
-		Headless: playwright.Bool(s.cfg.Headless),
-		Args: []string{
-			"--disable-blink-features=AutomationControlled",
-		},
-	})
+	session, err := s.newSession(s.cfg, s.fingerprints)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao abrir navegador: %w", err)
+		return nil, err
 	}
-	defer browser.Close()
+	defer session.Close()
 
-	context, err := browser.NewContext(playwright.BrowserNewContextOptions{
-		UserAgent: playwright.String(s.cfg.UserAgent),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar contexto: %w", err)
+	s.skips = make(SkipSummary)
+	defer s.logSkipSummary()
+
+	runStart := s.clock.Now()
+	s.complete = true
+	if err := s.loadState(); err != nil {
+		return nil, err
 	}
+	defer s.saveState(ctx, runStart)
 
-	page, err := context.NewPage()
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar página: %w", err)
+	// The run deadline is moved onto the scraper's clock once, so budgets
+	// are measured in the same time as the waits they cover.
+	var runDeadline time.Time
+	if deadline, ok := ctx.Deadline(); ok {
+		runDeadline = runStart.Add(time.Until(deadline))
 	}
 
 	var allProducts []domain.Product
 
-	for i, category := range s.cfg.Categories {
-		slog.Info("iniciando coleta", "category", category.Name)
+	// Categories are picked by name so a reload can add, drop or reorder
+	// the ones not yet scraped.
+	done := make(map[string]bool)
+	for {
+		if err := ctx.Err(); err != nil {
+			slog.Warn("coleta interrompida", "error", err)
+			break
+		}
 
-		products, err := s.scrapeCategory(ctx, page, category)
-		if err != nil {
-			slog.Error("erro ao scrapear categoria",
-				"category", category.Name,
-				"error", err,
-			)
-			continue
+		s.refreshConfig()
+		pending := s.pendingCategories(done)
+		if len(pending) == 0 {
+			break
 		}
+		category, pending := pending[0], pending[1:]
+		done[category.Name] = true
+
+		budget := category.TimeBudget
+		if !runDeadline.IsZero() {
+			budget = categoryBudget(runDeadline.Sub(s.clock.Now()), category, pending)
+		}
+		slog.Info("iniciando coleta", "category", category.Name, "budget", budget)
+
+		categoryCtx, cancel := ctx, context.CancelFunc(func() {})
+		if budget > 0 {
+			categoryCtx, cancel = s.clock.WithTimeout(ctx, budget)
+		}
+		products, err := s.scrapeCategory(categoryCtx, session, category)
+		cancel()
 
 		allProducts = append(allProducts, products...)
+		if err != nil {
+			s.complete = false
+			if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				slog.Warn("tempo da categoria esgotado",
+					"category", category.Name,
+					"budget", budget,
+					"products", len(products),
+				)
+				metrics.CategoryBudgetExhausted.WithLabelValues(category.Name).Inc()
+			} else {
+				slog.Error("erro ao scrapear categoria",
+					"category", category.Name,
+					"error", err,
+				)
+			}
+		}
 
 		// Pause
-		if i < len(s.cfg.Categories)-1 {
+		if len(s.pendingCategories(done)) > 0 {
 			slog.Info("pausa entre categorias", "duration", s.cfg.PageDelay)
-			time.Sleep(s.cfg.PageDelay)
+			s.clock.Sleep(ctx, s.cfg.PageDelay)
 		}
 	}
 
@@ -90,58 +156,126 @@ func (s *PichauScraper) Scrape(ctx context.Context) ([]domain.Product, error) {
 
 func (s *PichauScraper) scrapeCategory(
 	ctx context.Context,
-	page playwright.Page,
+	session pageSource,
 	category config.CategoryConfig,
 ) ([]domain.Product, error) {
+	listings, err := categoryListings(s.cfg.Store(StoreName), category)
+	if err != nil {
+		return nil, err
+	}
+
 	var products []domain.Product
+	for _, l := range listings {
+		listingProducts, err := s.scrapeListing(ctx, session, category, l)
+		products = append(products, listingProducts...)
+		if err != nil {
+			return products, err
+		}
+	}
+
+	return products, nil
+}
 
-	for pageNum := 1; pageNum <= s.cfg.MaxPages; pageNum++ {
+func (s *PichauScraper) scrapeListing(
+	ctx context.Context,
+	session pageSource,
+	category config.CategoryConfig,
+	l listing,
+) ([]domain.Product, error) {
+	var products []domain.Product
+	retries := 0
+	unchanged := 0
+	limits := s.cfg.Limits(category)
+
+	for pageNum := 1; ; pageNum++ {
 		select {
 		case <-ctx.Done():
 			return products, ctx.Err()
 		default:
 		}
 
-		startTime := time.Now()
+		if s.refreshConfig() {
+			current, ok := s.category(category.Name)
+			if !ok {
+				slog.Info("categoria removida da configuração", "category", category.Name)
+				return products, nil
+			}
+			category = current
+			limits = s.cfg.Limits(category)
+		}
+
+		if pageNum > limits.MaxPages {
+			break
+		}
+
+		page, err := session.Page()
+		if err != nil {
+			return products, err
+		}
+
+		startTime := s.clock.Now()
 
-		url := fmt.Sprintf("%s?page=%d", category.URL, pageNum)
+		url, err := l.pageURL(pageNum)
+		if err != nil {
+			return products, err
+		}
 		slog.Info("acessando página",
 			"category", category.Name,
+			"listing", l.label,
 			"page", pageNum,
 			"url", url,
 		)
 
-		if err := s.navigateToPage(page, url); err != nil {
+		if err := s.navigateToPage(page, url, limits.NavigationTimeout); err != nil {
+			if s.canRetry(session, &retries) {
+				pageNum--
+				continue
+			}
 			slog.Error("erro ao navegar",
 				"page", pageNum,
 				"error", err,
 			)
 			metrics.PagesProcessed.WithLabelValues(category.Name, "error").Inc()
+			s.complete = false
 			break
 		}
 
 		if s.detectCloudflare(page) {
 			slog.Warn("cloudflare detectado, aguardando resolução manual")
 			metrics.CloudflareDetections.Inc()
-			time.Sleep(s.cfg.CloudflareWait)
+			if err := s.clock.Sleep(ctx, s.cfg.CloudflareWait); err != nil {
+				return products, err
+			}
 		}
 
-		s.simulateHumanBehavior(page)
+		if err := s.simulateHumanBehavior(ctx, page); err != nil {
+			if ctx.Err() != nil {
+				return products, ctx.Err()
+			}
+			slog.Warn("erro ao simular comportamento", "error", err)
+		}
 
-		locator := page.Locator(".MuiCard-root")
-		count, err := locator.Count()
+		cards, err := readCards(page)
 		if err != nil {
-			slog.Error("erro ao contar cards", "error", err)
+			if s.canRetry(session, &retries) {
+				pageNum--
+				continue
+			}
+			slog.Error("erro ao ler cards", "error", err)
+			metrics.PagesProcessed.WithLabelValues(category.Name, "error").Inc()
+			s.complete = false
 			continue
 		}
 
-		if count == 0 {
+		if len(cards) == 0 {
 			slog.Warn("nenhum card encontrado, tentando novamente")
-			time.Sleep(5 * time.Second)
-			count, _ = locator.Count()
+			if err := s.clock.Sleep(ctx, 5*time.Second); err != nil {
+				return products, err
+			}
+			cards, _ = readCards(page)
 		}
 
-		if count == 0 {
+		if len(cards) == 0 {
 			slog.Warn("página vazia ou bloqueada",
 				"page", pageNum,
 				"category", category.Name,
@@ -150,37 +284,164 @@ func (s *PichauScraper) scrapeCategory(
 			break
 		}
 
-		pageProducts, duplicates := s.extractProducts(locator, category, pageNum)
+		retries = 0
+		pageProducts, duplicates, skipped := s.extractProducts(cards, category, pageNum)
 		products = append(products, pageProducts...)
+		s.skips.merge(category.Name, skipped)
 
 		// Metrics
-		duration := time.Since(startTime).Seconds()
+		duration := s.clock.Now().Sub(startTime).Seconds()
 		metrics.ScrapingDuration.WithLabelValues(category.Name).Observe(duration)
 		metrics.PagesProcessed.WithLabelValues(category.Name, "success").Inc()
 		metrics.ProductsScraped.WithLabelValues(category.Name).Add(float64(len(pageProducts)))
 		metrics.DuplicatesSkipped.WithLabelValues(category.Name).Add(float64(duplicates))
+		for reason, n := range skipped {
+			metrics.ProductsSkipped.WithLabelValues(category.Name, reason).Add(float64(n))
+		}
 
 		slog.Info("página processada",
 			"category", category.Name,
 			"page", pageNum,
 			"new_products", len(pageProducts),
 			"duplicates", duplicates,
+			"skipped", sumCounts(skipped),
 			"total", len(products),
 			"duration_seconds", fmt.Sprintf("%.2f", duration),
 		)
 
-		waitTime := s.randomWaitTime()
+		if s.state != nil {
+			if s.state.record(category.Name, pageProducts) {
+				unchanged = 0
+			} else {
+				unchanged++
+			}
+
+			if s.incremental && unchanged >= s.cfg.IncrementalStopAfter {
+				slog.Info("listagem sem alterações, encerrando paginação",
+					"category", category.Name,
+					"listing", l.label,
+					"unchanged_pages", unchanged,
+				)
+				metrics.IncrementalStops.WithLabelValues(category.Name).Inc()
+				break
+			}
+		}
+
+		waitTime := s.randomWaitTime(limits)
 		slog.Debug("aguardando próxima página", "duration", waitTime)
-		time.Sleep(waitTime)
+		if err := s.clock.Sleep(ctx, waitTime); err != nil {
+			return products, err
+		}
 	}
 
 	return products, nil
 }
 
-func (s *PichauScraper) navigateToPage(page playwright.Page, url string) error {
+// refreshConfig swaps in the config source's current value and reports
+// whether it changed. A behaviour profile that fails to build keeps the
+// previous one.
+func (s *PichauScraper) refreshConfig() bool {
+	if s.source == nil {
+		return false
+	}
+
+	next := s.source()
+	if next == nil || next == s.cfg {
+		return false
+	}
+	s.cfg = next
+
+	if b, err := newBehavior(next.Store(StoreName).Behavior, s.clock, s.rng); err == nil {
+		s.behavior = b
+	} else {
+		slog.Warn("perfil de comportamento mantido", "error", err)
+	}
+
+	slog.Info("nova configuração aplicada",
+		"categories", len(next.Categories),
+		"max_pages", next.MaxPages,
+	)
+	return true
+}
+
+func (s *PichauScraper) category(name string) (config.CategoryConfig, bool) {
+	for _, c := range s.cfg.Categories {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return config.CategoryConfig{}, false
+}
+
+// pendingCategories returns the configured categories not yet scraped, in
+// configuration order.
+func (s *PichauScraper) pendingCategories(done map[string]bool) []config.CategoryConfig {
+	var pending []config.CategoryConfig
+	for _, c := range s.cfg.Categories {
+		if !done[c.Name] {
+			pending = append(pending, c)
+		}
+	}
+	return pending
+}
+
+func (s *PichauScraper) loadState() error {
+	s.state, s.incremental = nil, false
+	if !s.cfg.Incremental {
+		return nil
+	}
+
+	state, err := loadObservationState(s.cfg.StateFile)
+	if err != nil {
+		return err
+	}
+
+	s.state = state
+	s.incremental = !state.fullRunDue(s.clock.Now(), s.cfg.FullRunInterval)
+
+	slog.Info("modo incremental",
+		"full_run", !s.incremental,
+		"last_full_run", state.LastFullRun,
+	)
+	return nil
+}
+
+// saveState persists the observations; a full run that was neither
+// interrupted nor hit a failing category or page also resets the full-run
+// interval, so the pages it missed are walked again next run.
+func (s *PichauScraper) saveState(ctx context.Context, runStart time.Time) {
+	if s.state == nil {
+		return
+	}
+
+	if !s.incremental {
+		if s.complete && ctx.Err() == nil {
+			s.state.LastFullRun = runStart
+		} else {
+			slog.Warn("execução completa com falhas, a próxima também será completa")
+		}
+	}
+
+	if err := s.state.save(s.cfg.StateFile); err != nil {
+		slog.Error("erro ao salvar estado incremental", "error", err)
+	}
+}
+
+// canRetry reports whether a failed page should be attempted again because
+// the browser died underneath it. The session relaunches on the next Page call.
+func (s *PichauScraper) canRetry(session pageSource, retries *int) bool {
+	if session.alive() || *retries >= s.cfg.RetryAttempts {
+		return false
+	}
+	*retries++
+	slog.Warn("navegador caiu, retomando página", "attempt", *retries)
+	return true
+}
+
+func (s *PichauScraper) navigateToPage(page playwright.Page, url string, timeout time.Duration) error {
 	_, err := page.Goto(url, playwright.PageGotoOptions{
 		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
-		Timeout:   playwright.Float(30000),
+		Timeout:   playwright.Float(float64(timeout.Milliseconds())),
 	})
 	return err
 }
@@ -190,29 +451,30 @@ func (s *PichauScraper) detectCloudflare(page playwright.Page) bool {
 	return strings.Contains(title, "Just a moment") || strings.Contains(title, "Cloudflare")
 }
 
-func (s *PichauScraper) simulateHumanBehavior(page playwright.Page) {
-	scrollAmount := float64(rand.Intn(500) + 300)
-	page.Mouse().Wheel(0, scrollAmount)
-	time.Sleep(time.Duration(rand.Intn(2000)+1000) * time.Millisecond)
+func (s *PichauScraper) simulateHumanBehavior(ctx context.Context, page playwright.Page) error {
+	startTime := s.clock.Now()
+	err := s.behavior.Simulate(ctx, page)
+
+	metrics.BehaviorDuration.
+		WithLabelValues(StoreName, s.behavior.Name()).
+		Observe(s.clock.Now().Sub(startTime).Seconds())
+
+	return err
 }
 
 func (s *PichauScraper) extractProducts(
-	locator playwright.Locator,
+	cards []cardData,
 	category config.CategoryConfig,
 	pageNum int,
-) ([]domain.Product, int) {
+) ([]domain.Product, int, map[string]int) {
 	var products []domain.Product
 	duplicates := 0
+	skipped := make(map[string]int)
 
-	items, err := locator.All()
-	if err != nil {
-		slog.Error("erro ao obter itens", "error", err)
-		return products, duplicates
-	}
-
-	for _, item := range items {
-		product, isDuplicate, err := s.extractProduct(item, category, pageNum)
+	for _, card := range cards {
+		product, isDuplicate, err := s.parseCard(card, category, pageNum)
 		if err != nil {
+			skipped[skipReason(err)]++
 			continue
 		}
 
@@ -224,19 +486,15 @@ func (s *PichauScraper) extractProducts(
 		products = append(products, product)
 	}
 
-	return products, duplicates
+	return products, duplicates, skipped
 }
 
-func (s *PichauScraper) extractProduct(
-	item playwright.Locator,
+func (s *PichauScraper) parseCard(
+	card cardData,
 	category config.CategoryConfig,
 	pageNum int,
 ) (domain.Product, bool, error) {
-	titleText, _ := item.Locator("h2").First().TextContent()
-	if titleText == "" {
-		titleText, _ = item.Locator(".MuiTypography-root").First().TextContent()
-	}
-
+	titleText, priceText := card.Title, card.Price
 	titleLower := strings.ToLower(titleText)
 
 	if len(category.Targets) > 0 {
@@ -249,42 +507,80 @@ func (s *PichauScraper) extractProduct(
 			}
 		}
 		if !found {
-			return domain.Product{}, false, fmt.Errorf("skip: fora dos alvos")
+			return domain.Product{}, false, ErrOutsideTargets
 		}
 	}
 
-	priceText, _ := item.Locator("text=/R\\$/").First().TextContent()
 	if titleText == "" || priceText == "" {
-		return domain.Product{}, false, fmt.Errorf("título ou preço vazio")
+		return domain.Product{}, false, ErrEmptyFields
 	}
 
 	if !strings.Contains(titleLower, category.Filter) {
-		return domain.Product{}, false, fmt.Errorf("não corresponde ao filtro")
+		return domain.Product{}, false, ErrFilterMismatch
 	}
 
-	price := parsePrice(priceText)
-	if price <= 0 {
-		return domain.Product{}, false, fmt.Errorf("preço inválido")
+	amount, currency, err := parsePrice(priceText)
+	if err != nil {
+		slog.Debug("preço não reconhecido",
+			"category", category.Name,
+			"raw_price", priceText,
+			"error", err,
+		)
+		return domain.Product{}, false, err
 	}
+	if amount <= 0 {
+		return domain.Product{}, false, ErrInvalidPrice
+	}
+	price := domain.Money{Cents: amount, Currency: currency}
 
 	titleClean := strings.TrimSpace(titleText)
-	brand := extractBrandFromTitle(titleClean)
-	key := fmt.Sprintf("%s|%.2f", titleClean, price)
+	condition, bundle := classifyCondition(category.Kind, titleClean)
+
+	product := domain.Product{
+		Title:      titleClean,
+		Brand:      extractBrandFromTitle(titleClean),
+		Price:      price,
+		RawPrice:   strings.TrimSpace(priceText),
+		Page:       pageNum,
+		Category:   category.Name,
+		Attributes: parseAttributes(category.Kind, titleClean),
+		Condition:  condition,
+		Bundle:     bundle,
+		ScrapedAt:  s.clock.Now(),
+	}
+
+	if err := conditionAllowed(category, product); err != nil {
+		return domain.Product{}, false, err
+	}
 
+	key := fmt.Sprintf("%s|%d", titleClean, price.Cents)
 	if s.seen[key] {
 		return domain.Product{}, true, nil
 	}
-
 	s.seen[key] = true
 
-	return domain.Product{
-		Title:    titleClean,
-		Brand:    brand,
-		Price:    price,
-		RawPrice: strings.TrimSpace(priceText),
-		Page:     pageNum,
-		Category: category.Name,
-	}, false, nil
+	applyPromotion(&product, card)
+	applyRating(&product, card)
+
+	return product, false, nil
+}
+
+func (s *PichauScraper) logSkipSummary() {
+	for _, category := range s.skips.Categories() {
+		args := []any{"category", category, "total", s.skips.Total(category)}
+		for _, reason := range s.skips.Reasons(category) {
+			args = append(args, reason, s.skips[category][reason])
+		}
+		slog.Info("resumo de cards descartados", args...)
+	}
+}
+
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
 }
 
 var commonBrands = []string{
@@ -292,39 +588,34 @@ var commonBrands = []string{
 	"INTEL", "AMD", "CORSAIR", "KINGSTON", "XPG", "LOGITECH",
 	"RAZER", "REDRAGON", "SAMSUNG", "LG", "AOC", "HUSKY",
 	"MANCER", "PICHAU", "NVIDIA", "ZOTAC", "COLORFUL", "GAINWARD",
-	"SAPPHIRE", "POWERCOLOR", "XFX", "INNO3D",
+	"SAPPHIRE", "POWERCOLOR", "XFX", "INNO3D", "BIOSTAR", "ADATA",
+	"CRUCIAL", "TEAMGROUP", "WESTERN DIGITAL", "SEAGATE", "LEXAR",
+	"SEASONIC", "EVGA", "COOLER MASTER", "DEEPCOOL", "THERMALTAKE",
+	"NZXT", "LIAN LI", "RISE MODE", "SUPERFRAME", "GAMEMAX", "MONTECH",
 }
 
+// brandPatterns match whole words only, so "LG" does not match "LGA1700".
+var brandPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(commonBrands))
+	for i, brand := range commonBrands {
+		patterns[i] = regexp.MustCompile(`\b` + regexp.QuoteMeta(brand) + `\b`)
+	}
+	return patterns
+}()
+
 func extractBrandFromTitle(title string) string {
 	titleUpper := strings.ToUpper(title)
-	for _, brand := range commonBrands {
-		if strings.Contains(titleUpper, brand) {
-			return brand
+	for i, pattern := range brandPatterns {
+		if pattern.MatchString(titleUpper) {
+			return commonBrands[i]
 		}
 	}
 	return "OUTROS"
 }
 
-func (s *PichauScraper) randomWaitTime() time.Duration {
-	min := s.cfg.WaitTimeMin.Seconds()
-	max := s.cfg.WaitTimeMax.Seconds()
-	wait := min + rand.Float64()*(max-min)
+func (s *PichauScraper) randomWaitTime(limits config.CategoryLimits) time.Duration {
+	min := limits.WaitTimeMin.Seconds()
+	max := limits.WaitTimeMax.Seconds()
+	wait := min + s.rng.Float64()*(max-min)
 	return time.Duration(wait * float64(time.Second))
 }
-
-func parsePrice(raw string) float64 {
-	clean := strings.ReplaceAll(raw, "R$", "")
-	clean = strings.ReplaceAll(clean, "R$Â", "")
-	clean = strings.ReplaceAll(clean, "Â", "")
-	clean = strings.ReplaceAll(clean, ".", "")
-	clean = strings.ReplaceAll(clean, ",", ".")
-	clean = strings.TrimSpace(clean)
-
-	fields := strings.Fields(clean)
-	if len(fields) > 0 {
-		clean = fields[len(fields)-1]
-	}
-
-	value, _ := strconv.ParseFloat(clean, 64)
-	return value
-}
@@ -1,48 +1,379 @@
 package scraper
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	neturl "net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/vitor-labes/pc-scraper/internal/config"
+	"github.com/vitor-labes/pc-scraper/internal/domain"
 )
 
-func TestParsePrice(t *testing.T) {
+func TestParseCardSkipReasons(t *testing.T) {
+	category := config.CategoryConfig{
+		Name:    "GPU",
+		Filter:  "placa",
+		Targets: []string{"RTX 4060"},
+	}
+
 	tests := []struct {
-		name     string
-		input    string
-		expected float64
+		name   string
+		title  string
+		price  string
+		reason string
 	}{
-		{
-			name:     "preço normal",
-			input:    "R$ 1.234,56",
-			expected: 1234.56,
-		},
-		{
-			name:     "preço com caractere especial",
-			input:    "R$Â 941,16",
-			expected: 941.16,
-		},
-		{
-			name:     "preço sem centavos",
-			input:    "R$ 1.000",
-			expected: 1000.00,
-		},
-		{
-			name:     "preço com espaços extras",
-			input:    "  R$ 599,99  ",
-			expected: 599.99,
-		},
-		{
-			name:     "preço inválido",
-			input:    "R$ abc",
-			expected: 0,
-		},
+		{"fora dos alvos", "Placa de Video RX 7600", "R$ 1.999,99", "outside_targets"},
+		{"preço vazio", "Placa de Video RTX 4060", "", "empty_fields"},
+		{"filtro", "Notebook RTX 4060", "R$ 5.999,99", "filter_mismatch"},
+		{"preço não reconhecido", "Placa de Video RTX 4060", "R$ abc", "unparseable_price"},
+		{"preço zerado", "Placa de Video RTX 4060", "R$ 0,00", "invalid_price"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parsePrice(tt.input)
-			if got != tt.expected {
-				t.Errorf("parsePrice(%q) = %f, want %f", tt.input, got, tt.expected)
+			s := NewPichauScraper(config.NewDefault())
+			_, _, err := s.parseCard(cardData{Title: tt.title, Price: tt.price}, category, 1)
+			if err == nil {
+				t.Fatalf("parseCard(%q, %q) esperava erro", tt.title, tt.price)
+			}
+			if got := skipReason(err); got != tt.reason {
+				t.Errorf("skipReason() = %q, want %q", got, tt.reason)
 			}
 		})
 	}
 }
+
+func TestParseCardDuplicate(t *testing.T) {
+	category := config.CategoryConfig{Name: "GPU", Filter: "placa"}
+	s := NewPichauScraper(config.NewDefault())
+
+	product, dup, err := s.parseCard(cardData{Title: "Placa de Video ASUS RTX 4060", Price: "R$ 1.999,99"}, category, 1)
+	if err != nil || dup {
+		t.Fatalf("primeira leitura: dup=%v err=%v", dup, err)
+	}
+	if product.Brand != "ASUS" || product.Price != domain.BRL(199999) {
+		t.Errorf("produto inesperado: %+v", product)
+	}
+
+	_, dup, err = s.parseCard(cardData{Title: "Placa de Video ASUS RTX 4060", Price: "R$ 1.999,99"}, category, 2)
+	if err != nil || !dup {
+		t.Errorf("segunda leitura deveria ser duplicada: dup=%v err=%v", dup, err)
+	}
+}
+
+type fakeClock struct {
+	now    time.Time
+	slept  time.Duration
+	sleeps int
+	// onSleep runs before each sleep; tests use it to cancel mid-run.
+	onSleep func(n int)
+	timers  []*virtualTimeout
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps++
+	if c.onSleep != nil {
+		c.onSleep(c.sleeps)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.now = c.now.Add(d)
+	c.slept += d
+
+	// Timeouts that passed during the sleep end it, as a real timer would.
+	for _, t := range c.timers {
+		if !c.now.Before(t.deadline) {
+			t.expire(context.DeadlineExceeded)
+		}
+	}
+	return ctx.Err()
+}
+
+func (c *fakeClock) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	inner, cancel := context.WithCancel(ctx)
+	t := &virtualTimeout{Context: inner, cancel: cancel, deadline: c.now.Add(d)}
+	if d <= 0 {
+		t.expire(context.DeadlineExceeded)
+	}
+	c.timers = append(c.timers, t)
+	return t, func() { t.expire(context.Canceled) }
+}
+
+// virtualTimeout is a context that expires on fakeClock time instead of a
+// real timer; cancelling the parent still ends it.
+type virtualTimeout struct {
+	context.Context
+	cancel   context.CancelFunc
+	deadline time.Time
+	err      error
+}
+
+func (t *virtualTimeout) expire(err error) {
+	if t.err == nil && t.Context.Err() == nil {
+		t.err = err
+	}
+	t.cancel()
+}
+
+func (t *virtualTimeout) Deadline() (time.Time, bool) { return t.deadline, true }
+
+func (t *virtualTimeout) Err() error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.Context.Err()
+}
+
+type fakeMouse struct {
+	playwright.Mouse
+}
+
+func (fakeMouse) Wheel(float64, float64) error { return nil }
+
+func (fakeMouse) Move(float64, float64, ...playwright.MouseMoveOptions) error { return nil }
+
+// fakePage serves pages 1..pages of a listing with cardsPerPage cards each.
+type fakePage struct {
+	playwright.Page
+	pages        int
+	cardsPerPage int
+	current      int
+	visited      []string
+	// onGoto, when set, can fail a navigation before it happens.
+	onGoto func(url string) error
+	// unreadable pages fail both card extraction paths.
+	unreadable map[int]bool
+}
+
+func (p *fakePage) Goto(url string, _ ...playwright.PageGotoOptions) (playwright.Response, error) {
+	if p.onGoto != nil {
+		if err := p.onGoto(url); err != nil {
+			return nil, err
+		}
+	}
+	p.visited = append(p.visited, url)
+	p.current = 0
+	if u, err := neturl.Parse(url); err == nil {
+		p.current, _ = strconv.Atoi(u.Query().Get("page"))
+	}
+	return nil, nil
+}
+
+func (p *fakePage) Title() (string, error) { return "Pichau", nil }
+
+func (p *fakePage) Mouse() playwright.Mouse { return fakeMouse{} }
+
+func (p *fakePage) IsClosed() bool { return false }
+
+func (p *fakePage) Evaluate(string, ...interface{}) (interface{}, error) {
+	if p.unreadable[p.current] {
+		return nil, errors.New("execution context was destroyed")
+	}
+	var cards []cardData
+	if p.current >= 1 && p.current <= p.pages {
+		for i := 0; i < p.cardsPerPage; i++ {
+			cards = append(cards, cardData{
+				Title: fmt.Sprintf("Placa de Video RTX 4060 P%d-%d", p.current, i),
+				Price: fmt.Sprintf("R$ %d,99", 1000+p.current*10+i),
+			})
+		}
+		cards = append(cards, cardData{Title: "Cabo HDMI", Price: "R$ 19,90"})
+	}
+	raw, err := json.Marshal(cards)
+	return string(raw), err
+}
+
+func (p *fakePage) Locator(string, ...playwright.PageLocatorOptions) playwright.Locator {
+	return &fakeLocator{allErr: errors.New("página indisponível")}
+}
+
+// fakeSession serves one page; a dead session comes back on the next Page
+// call, as browserSession relaunches.
+type fakeSession struct {
+	page     *fakePage
+	dead     bool
+	restarts int
+}
+
+func (s *fakeSession) Page() (playwright.Page, error) {
+	if s.dead {
+		s.dead = false
+		s.restarts++
+	}
+	return s.page, nil
+}
+
+func (s *fakeSession) alive() bool { return !s.dead }
+
+func (s *fakeSession) Close() {}
+
+func newVirtualScraper(cfg *config.Config, clock *fakeClock, page *fakePage) *PichauScraper {
+	s := NewPichauScraper(cfg,
+		WithClock(clock),
+		WithRand(rand.New(rand.NewSource(42))),
+	)
+	s.newSession = func(*config.Config, *fingerprintPool) (pageSource, error) {
+		return &fakeSession{page: page}, nil
+	}
+	return s
+}
+
+func virtualConfig() *config.Config {
+	cfg := config.NewDefault()
+	cfg.Categories = []config.CategoryConfig{
+		{Name: "GPU", URL: "https://www.pichau.com.br/hardware/placa-de-video", Filter: "placa"},
+	}
+	return cfg
+}
+
+func TestScrapeRunsInVirtualTime(t *testing.T) {
+	cfg := virtualConfig()
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	page := &fakePage{pages: 5, cardsPerPage: 3}
+
+	started := time.Now()
+	products, err := newVirtualScraper(cfg, clock, page).Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape() erro: %v", err)
+	}
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Scrape() levou %v em tempo real", elapsed)
+	}
+	if len(products) != 15 {
+		t.Errorf("len(products) = %d, want 15", len(products))
+	}
+	if len(page.visited) != 5 {
+		t.Errorf("páginas visitadas = %d, want 5", len(page.visited))
+	}
+
+	minWait := time.Duration(cfg.MaxPages) * cfg.WaitTimeMin
+	if clock.slept < minWait {
+		t.Errorf("tempo virtual = %v, want >= %v", clock.slept, minWait)
+	}
+}
+
+func TestScrapeIsDeterministicWithSeededRand(t *testing.T) {
+	run := func() time.Duration {
+		clock := &fakeClock{}
+		page := &fakePage{pages: 5, cardsPerPage: 2}
+		if _, err := newVirtualScraper(virtualConfig(), clock, page).Scrape(context.Background()); err != nil {
+			t.Fatalf("Scrape() erro: %v", err)
+		}
+		return clock.slept
+	}
+
+	if first, second := run(), run(); first != second {
+		t.Errorf("tempo virtual diferente entre execuções: %v != %v", first, second)
+	}
+}
+
+func TestScrapeCategoryStopsWhenContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := &fakeClock{onSleep: func(n int) {
+		if n == 3 {
+			cancel()
+		}
+	}}
+	page := &fakePage{pages: 5, cardsPerPage: 2}
+	s := newVirtualScraper(virtualConfig(), clock, page)
+	behavior, _ := newBehavior("light", clock, s.rng)
+	s.behavior = behavior
+
+	_, err := s.scrapeCategory(ctx, &fakeSession{page: page}, s.cfg.Categories[0])
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("scrapeCategory() erro = %v, want context.Canceled", err)
+	}
+	if len(page.visited) > 2 {
+		t.Errorf("continuou navegando após cancelamento: %d páginas", len(page.visited))
+	}
+}
+
+func TestScrapeAppliesConfigSourceBetweenPages(t *testing.T) {
+	initial := virtualConfig()
+
+	reloaded := virtualConfig()
+	reloaded.MaxPages = 2
+	reloaded.Categories[0].Targets = []string{"P2-0"}
+	reloaded.Categories = append(reloaded.Categories, config.CategoryConfig{
+		Name: "CPU", URL: "https://www.pichau.com.br/hardware/processadores", Filter: "placa",
+	})
+
+	clock := &fakeClock{}
+	page := &fakePage{pages: 5, cardsPerPage: 3}
+	s := newVirtualScraper(initial, clock, page)
+	s.source = func() *config.Config {
+		if len(page.visited) == 0 {
+			return initial
+		}
+		return reloaded
+	}
+
+	products, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape() erro: %v", err)
+	}
+
+	// Page 1 with the initial config, page 2 filtered by the new targets.
+	gpu := 0
+	for _, p := range products {
+		if p.Category == "GPU" {
+			gpu++
+		}
+	}
+	if gpu != 4 {
+		t.Errorf("produtos GPU = %d, want 4", gpu)
+	}
+	if len(page.visited) != 4 {
+		t.Fatalf("páginas visitadas = %d, want 4 (2 GPU + 2 CPU)", len(page.visited))
+	}
+	if !strings.Contains(page.visited[2], "processadores") {
+		t.Errorf("categoria adicionada não coletada: %v", page.visited)
+	}
+}
+
+func TestScrapeRetriesPageAfterBrowserCrash(t *testing.T) {
+	clock := &fakeClock{}
+	page := &fakePage{pages: 3, cardsPerPage: 2}
+	session := &fakeSession{page: page}
+	cfg := virtualConfig()
+	cfg.MaxPages = 3
+	s := newVirtualScraper(cfg, clock, page)
+	s.newSession = func(*config.Config, *fingerprintPool) (pageSource, error) {
+		return session, nil
+	}
+
+	crashed := false
+	page.onGoto = func(url string) error {
+		if !crashed && strings.Contains(url, "page=2") {
+			crashed = true
+			session.dead = true
+			return errors.New("Target crashed")
+		}
+		return nil
+	}
+
+	products, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape() erro: %v", err)
+	}
+	if session.restarts != 1 {
+		t.Errorf("reinícios = %d, want 1", session.restarts)
+	}
+	if len(products) != 6 || len(page.visited) != 3 {
+		t.Errorf("produtos = %d, páginas = %d, want 6 e 3", len(products), len(page.visited))
+	}
+}
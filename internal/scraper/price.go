@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+const (
+	currencyUSD = "USD"
+	currencyEUR = "EUR"
+)
+
+// priceMojibake undoes UTF-8 text that was decoded as Latin-1/Windows-1252
+// somewhere on the way, which turns "R$ " into "R$Â ".
+var priceMojibake = strings.NewReplacer(
+	"\u00e2\u201a\u00ac", "€",
+	"\u00c2\u00a0", " ", // non-breaking space
+	"\u00c3\u00a0", "à",
+	"\u00c2", "",
+	"\u00a0", " ",
+	"\u202f", " ", // narrow non-breaking space
+	"\u2009", " ", // thin space
+)
+
+var (
+	// priceToken is an amount with an optional currency marker before or
+	// after it. Spaces only count as thousands separators before a group of
+	// exactly three digits, as in "1 299,00 €".
+	priceToken = regexp.MustCompile(
+		`(?i)(R\$|US\$|U\$|\$|€|BRL|USD|EUR)?\s*(\d+(?:[.,]\d+|\s\d{3}\b)*)(\s*(?:€|BRL\b|USD\b|EUR\b))?`)
+
+	installmentPrefix = regexp.MustCompile(`(?i)\d+\s*x\s*(?:sem juros\s*)?(?:de\s*)?$`)
+	salePrefix        = regexp.MustCompile(`(?i)\bpor\s*:?\s*$`)
+	cashSuffix        = regexp.MustCompile(`(?i)^\s*(?:à|a)\s+vista`)
+	rangeSeparator    = regexp.MustCompile(`(?i)^\s*(?:-|–|—|a|até)\s*$`)
+)
+
+var currencySymbols = map[string]string{
+	"r$":  domain.CurrencyBRL,
+	"brl": domain.CurrencyBRL,
+	"us$": currencyUSD,
+	"u$":  currencyUSD,
+	"$":   currencyUSD,
+	"usd": currencyUSD,
+	"€":   currencyEUR,
+	"eur": currencyEUR,
+}
+
+type priceCandidate struct {
+	start, end  int
+	number      string
+	currency    string
+	hasCurrency bool
+}
+
+// parsePrice reads the selling price from a card's price text and returns
+// it in cents with its ISO currency code. It understands BRL/EUR
+// ("1.234,56") and USD ("1,234.56") separators, non-breaking spaces and
+// mojibake, and picks one amount out of texts such as
+// "de R$ 2.499,99 por R$ 1.999,99", "R$ 99,90 - R$ 129,90" (the lower
+// bound) or "R$ 1.899,90 à vista ou 10x de R$ 199,99". Amounts without a
+// currency are BRL, the store's currency. Errors wrap ErrUnparseablePrice.
+func parsePrice(raw string) (int64, string, error) {
+	text := priceMojibake.Replace(raw)
+
+	candidates := findPriceCandidates(text)
+	if len(candidates) == 0 {
+		return 0, "", fmt.Errorf("%w: nenhum valor em %q", ErrUnparseablePrice, strings.TrimSpace(raw))
+	}
+
+	chosen := selectPrice(text, candidates)
+	amount, err := parseAmount(chosen.number)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %v", ErrUnparseablePrice, err)
+	}
+	return amount, chosen.currency, nil
+}
+
+// findPriceCandidates returns the amounts in text, leaving out installment
+// counts ("10x"), installment values ("10x de R$ 199,99") and percentages.
+// When any amount carries a currency, bare numbers are dropped too.
+func findPriceCandidates(text string) []priceCandidate {
+	var all []priceCandidate
+	anyCurrency := false
+
+	for _, m := range priceToken.FindAllStringSubmatchIndex(text, -1) {
+		c := priceCandidate{start: m[0], end: m[1], number: text[m[4]:m[5]], currency: domain.CurrencyBRL}
+
+		symbol := ""
+		if m[2] >= 0 {
+			symbol = text[m[2]:m[3]]
+		} else if m[6] >= 0 {
+			symbol = strings.TrimSpace(text[m[6]:m[7]])
+		}
+		if symbol != "" {
+			c.currency = currencySymbols[strings.ToLower(symbol)]
+			c.hasCurrency = true
+		}
+
+		rest := strings.TrimLeft(text[c.end:], " ")
+		if !c.hasCurrency && (strings.HasPrefix(rest, "x") || strings.HasPrefix(rest, "X") || strings.HasPrefix(rest, "%")) {
+			continue
+		}
+		if installmentPrefix.MatchString(text[:c.start]) {
+			continue
+		}
+
+		anyCurrency = anyCurrency || c.hasCurrency
+		all = append(all, c)
+	}
+
+	if !anyCurrency {
+		return all
+	}
+	var withCurrency []priceCandidate
+	for _, c := range all {
+		if c.hasCurrency {
+			withCurrency = append(withCurrency, c)
+		}
+	}
+	return withCurrency
+}
+
+// selectPrice prefers the amount after "por" or before "à vista", then the
+// lower bound of a range, and otherwise the last amount, since cards list
+// the struck-through price before the current one.
+func selectPrice(text string, candidates []priceCandidate) priceCandidate {
+	for _, c := range candidates {
+		if salePrefix.MatchString(text[:c.start]) || cashSuffix.MatchString(text[c.end:]) {
+			return c
+		}
+	}
+	for i := 0; i+1 < len(candidates); i++ {
+		if rangeSeparator.MatchString(text[candidates[i].end:candidates[i+1].start]) {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// parseAmount converts a number such as "1.234,56", "1,234.56", "1 299" or
+// "99" to cents. The last separator is the decimal one when it is followed
+// by one or two digits; a separator followed by three digits groups
+// thousands, whatever the locale.
+func parseAmount(number string) (int64, error) {
+	s := strings.ReplaceAll(number, " ", "")
+
+	whole, frac := s, ""
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 {
+		digits := len(s) - i - 1
+		switch {
+		case digits == 1 || digits == 2:
+			whole, frac = s[:i], s[i+1:]
+			if strings.ContainsRune(whole, rune(s[i])) {
+				return 0, fmt.Errorf("separadores inconsistentes em %q", number)
+			}
+		case digits != 3:
+			return 0, fmt.Errorf("casas decimais inválidas em %q", number)
+		}
+	}
+
+	groups := strings.FieldsFunc(whole, func(r rune) bool { return r == '.' || r == ',' })
+	if len(groups) > 1 {
+		sep := whole[len(groups[0])]
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return 0, fmt.Errorf("separador de milhar inválido em %q", number)
+			}
+		}
+		if strings.Count(whole, string(sep)) != len(groups)-1 {
+			return 0, fmt.Errorf("separadores inconsistentes em %q", number)
+		}
+	}
+
+	frac += strings.Repeat("0", 2-len(frac))
+	cents, err := strconv.ParseInt(strings.Join(groups, "")+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido %q", number)
+	}
+	return cents, nil
+}
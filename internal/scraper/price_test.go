@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		cents    int64
+		currency string
+	}{
+		{"preço normal", "R$ 1.234,56", 123456, "BRL"},
+		{"preço sem centavos", "R$ 1.000", 100000, "BRL"},
+		{"preço com espaços extras", "  R$ 599,99  ", 59999, "BRL"},
+		{"sem símbolo", "1.899,90", 189990, "BRL"},
+		{"um dígito decimal", "R$ 12,5", 1250, "BRL"},
+		{"milhões", "R$ 1.234.567,89", 123456789, "BRL"},
+
+		{"espaço não separável", "R$\u00a01.899,90", 189990, "BRL"},
+		{"espaço estreito", "R$\u202f1.899,90", 189990, "BRL"},
+		{"mojibake com espaço", "R$\u00c2\u00a0941,16", 94116, "BRL"},
+		{"mojibake sem espaço", "R$\u00c2 941,16", 94116, "BRL"},
+		{"mojibake do euro", "1.299,00 \u00e2\u201a\u00ac", 129900, "EUR"},
+
+		{"dólar", "$1,234.56", 123456, "USD"},
+		{"dólar com código", "USD 99.99", 9999, "USD"},
+		{"dólar sem centavos", "US$ 1,000", 100000, "USD"},
+		{"euro com símbolo antes", "€ 1.299,00", 129900, "EUR"},
+		{"euro com símbolo depois", "1 299,00 €", 129900, "EUR"},
+		{"euro com código", "EUR 12,50", 1250, "EUR"},
+		{"euro em formato inglês", "€1,299.00", 129900, "EUR"},
+
+		{"de/por", "de R$ 2.499,99 por R$ 1.999,99", 199999, "BRL"},
+		{"de/por com dois pontos", "De: R$ 2.499,99 Por: R$ 1.999,99", 199999, "BRL"},
+		{"preço riscado e atual", "R$ 2.499,99 R$ 1.999,99", 199999, "BRL"},
+		{"faixa com hífen", "R$ 99,90 - R$ 129,90", 9990, "BRL"},
+		{"faixa com até", "R$ 99,90 até R$ 129,90", 9990, "BRL"},
+
+		{"parcelado depois", "R$ 1.899,90 à vista ou 10x de R$ 199,99", 189990, "BRL"},
+		{"parcelado antes", "10x de R$ 199,99 sem juros ou R$ 1.799,90 à vista", 179990, "BRL"},
+		{"parcelado sem juros de", "12x sem juros de R$ 166,66 R$ 1.999,90", 199990, "BRL"},
+		{"à vista com mojibake", "R$ 1.899,90 \u00c3\u00a0 vista", 189990, "BRL"},
+		{"desconto percentual", "15% OFF R$ 1.699,90", 169990, "BRL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cents, currency, err := parsePrice(tt.input)
+			if err != nil {
+				t.Fatalf("parsePrice(%q) error: %v", tt.input, err)
+			}
+			if cents != tt.cents || currency != tt.currency {
+				t.Errorf("parsePrice(%q) = %d %s, want %d %s", tt.input, cents, currency, tt.cents, tt.currency)
+			}
+		})
+	}
+}
+
+func TestParsePriceErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"R$ abc",
+		"Indisponível",
+		"R$ 1.899,999",
+		"R$ 1.89.90",
+		"R$ 1.2345,00",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, _, err := parsePrice(input)
+			if !errors.Is(err, ErrUnparseablePrice) {
+				t.Errorf("parsePrice(%q) error = %v, want ErrUnparseablePrice", input, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package scraper
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+var (
+	discountBadgePattern = regexp.MustCompile(`(?i)\b(\d{1,3})\s*%\s*OFF`)
+	// Codes are written in upper case, which keeps "com cupom de desconto"
+	// from yielding a code.
+	couponCodePattern = regexp.MustCompile(`(?i:cupom)\s*:?\s*([A-Z0-9][A-Z0-9_-]{2,})\b`)
+)
+
+// applyPromotion fills the original price, advertised discount and coupon
+// fields from the card. Without a "% OFF" badge the advertised discount is
+// derived from the struck-through price.
+func applyPromotion(product *domain.Product, card cardData) {
+	// A missing or unreadable struck-through price just means no promotion.
+	if amount, currency, err := parsePrice(card.OriginalPrice); err == nil &&
+		currency == product.Price.Currency && amount > product.Price.Cents {
+		product.OriginalPrice = domain.Money{Cents: amount, Currency: currency}
+	}
+
+	if m := discountBadgePattern.FindStringSubmatch(card.Discount); m != nil {
+		discount, _ := strconv.ParseFloat(m[1], 64)
+		product.AdvertisedDiscount = min(discount, 100)
+	} else if !product.OriginalPrice.IsZero() {
+		discount := (1 - product.Price.Float64()/product.OriginalPrice.Float64()) * 100
+		product.AdvertisedDiscount = math.Round(discount*100) / 100
+	}
+
+	coupon := strings.Join(strings.Fields(card.Coupon), " ")
+	if coupon == "" {
+		return
+	}
+	if m := couponCodePattern.FindStringSubmatch(coupon); m != nil {
+		product.CouponCode = m[1]
+	}
+	product.CouponCondition = coupon
+}
@@ -0,0 +1,82 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+func TestApplyPromotion(t *testing.T) {
+	tests := []struct {
+		name string
+		card cardData
+		want domain.Product
+	}{
+		{
+			name: "selo e cupom",
+			card: cardData{
+				OriginalPrice: "R$ 2.499,99",
+				Discount:      "15% OFF",
+				Coupon:        "Use o cupom  PICHAU10 em compras acima de R$ 1.000",
+			},
+			want: domain.Product{
+				Price:              domain.BRL(199999),
+				OriginalPrice:      domain.BRL(249999),
+				AdvertisedDiscount: 15,
+				CouponCode:         "PICHAU10",
+				CouponCondition:    "Use o cupom PICHAU10 em compras acima de R$ 1.000",
+			},
+		},
+		{
+			name: "selo de 100%",
+			card: cardData{Discount: "100% OFF"},
+			want: domain.Product{
+				Price:              domain.BRL(200000),
+				AdvertisedDiscount: 100,
+			},
+		},
+		{
+			name: "selo acima de 100% é limitado",
+			card: cardData{Discount: "120% OFF"},
+			want: domain.Product{
+				Price:              domain.BRL(200000),
+				AdvertisedDiscount: 100,
+			},
+		},
+		{
+			name: "desconto derivado do preço riscado",
+			card: cardData{OriginalPrice: "R$ 2.500,00"},
+			want: domain.Product{
+				Price:              domain.BRL(200000),
+				OriginalPrice:      domain.BRL(250000),
+				AdvertisedDiscount: 20,
+			},
+		},
+		{
+			name: "dica de cupom sem código",
+			card: cardData{Coupon: "Desconto com cupom de desconto no carrinho"},
+			want: domain.Product{
+				Price:           domain.BRL(200000),
+				CouponCondition: "Desconto com cupom de desconto no carrinho",
+			},
+		},
+		{
+			name: "preço riscado menor que o atual é ignorado",
+			card: cardData{OriginalPrice: "R$ 100,00"},
+			want: domain.Product{Price: domain.BRL(200000)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{Price: tt.want.Price}
+			applyPromotion(&product, tt.card)
+			if product.OriginalPrice != tt.want.OriginalPrice ||
+				product.AdvertisedDiscount != tt.want.AdvertisedDiscount ||
+				product.CouponCode != tt.want.CouponCode ||
+				product.CouponCondition != tt.want.CouponCondition {
+				t.Errorf("applyPromotion() = %+v, want %+v", product, tt.want)
+			}
+		})
+	}
+}
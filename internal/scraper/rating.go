@@ -0,0 +1,35 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+var (
+	ratingPattern      = regexp.MustCompile(`(\d(?:[.,]\d+)?)`)
+	reviewCountPattern = regexp.MustCompile(`(?i)\((\d+)\)|(\d+)\s+avalia`)
+)
+
+const maxRating = 5
+
+// applyRating parses the star label ("4.5 Stars", "Avaliação 4,5 de 5") and
+// the review count shown next to it ("(123)" or "123 avaliações").
+func applyRating(product *domain.Product, card cardData) {
+	if m := ratingPattern.FindStringSubmatch(card.Rating); m != nil {
+		rating, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64)
+		if err == nil && rating >= 0 && rating <= maxRating {
+			product.Rating = rating
+		}
+	}
+
+	if m := reviewCountPattern.FindStringSubmatch(card.Reviews); m != nil {
+		count := m[1]
+		if count == "" {
+			count = m[2]
+		}
+		product.ReviewCount, _ = strconv.Atoi(count)
+	}
+}
@@ -0,0 +1,28 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/vitor-labes/pc-scraper/internal/domain"
+)
+
+func TestApplyRating(t *testing.T) {
+	tests := []struct {
+		card    cardData
+		rating  float64
+		reviews int
+	}{
+		{cardData{Rating: "4.5 Stars", Reviews: "4.5 Stars(123)"}, 4.5, 123},
+		{cardData{Rating: "Avaliação 4,8 de 5", Reviews: "Avaliação 4,8 de 5 37 avaliações"}, 4.8, 37},
+		{cardData{Rating: "", Reviews: ""}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		var p domain.Product
+		applyRating(&p, tt.card)
+		if p.Rating != tt.rating || p.ReviewCount != tt.reviews {
+			t.Errorf("applyRating(%+v) = (%v, %d), want (%v, %d)",
+				tt.card, p.Rating, p.ReviewCount, tt.rating, tt.reviews)
+		}
+	}
+}
@@ -6,14 +6,57 @@ CREATE TABLE IF NOT EXISTS products (
     raw_price VARCHAR(50),
     page_number INTEGER,
     category VARCHAR(50) NOT NULL,
-    scraped_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    attributes JSONB,
+    original_price DECIMAL(10, 2),
+    advertised_discount DECIMAL(5, 2),
+    coupon_code VARCHAR(50),
+    coupon_condition VARCHAR(255),
+    condition VARCHAR(20) NOT NULL DEFAULT 'new',
+    is_bundle BOOLEAN NOT NULL DEFAULT FALSE,
+    rating DECIMAL(3, 2),
+    review_count INTEGER,
+    currency CHAR(3) NOT NULL DEFAULT 'BRL',
+    message_id VARCHAR(64),
+    scraped_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE INDEX idx_products_category ON products(category);
-CREATE INDEX idx_products_price ON products(price);
-CREATE INDEX idx_products_scraped_at ON products(scraped_at);
-CREATE INDEX idx_products_title ON products(title);
+-- Columns added after the first release, for databases created before them.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS original_price DECIMAL(10, 2);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS advertised_discount DECIMAL(5, 2);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS coupon_code VARCHAR(50);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS coupon_condition VARCHAR(255);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS condition VARCHAR(20) NOT NULL DEFAULT 'new';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS is_bundle BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating DECIMAL(3, 2);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS review_count INTEGER;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS message_id VARCHAR(64);
+
+-- scraped_at used to be a TIMESTAMP without time zone; existing values are
+-- taken as UTC. The view depending on it is recreated below.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema()
+            AND table_name = 'products' AND column_name = 'scraped_at'
+            AND data_type = 'timestamp without time zone'
+    ) THEN
+        DROP VIEW IF EXISTS v_best_prices;
+        ALTER TABLE products ALTER COLUMN scraped_at TYPE TIMESTAMPTZ
+            USING scraped_at AT TIME ZONE 'UTC';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+CREATE INDEX IF NOT EXISTS idx_products_price ON products(price);
+CREATE INDEX IF NOT EXISTS idx_products_scraped_at ON products(scraped_at);
+CREATE INDEX IF NOT EXISTS idx_products_title ON products(title);
+-- One row per queue message, so redeliveries are not stored twice. Legacy
+-- messages have no ID and are never treated as duplicates.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_message_id ON products(message_id);
 
 CREATE TABLE IF NOT EXISTS price_history (
     id SERIAL PRIMARY KEY,
@@ -24,8 +67,8 @@ CREATE TABLE IF NOT EXISTS price_history (
     changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE INDEX idx_price_history_title ON price_history(product_title);
-CREATE INDEX idx_price_history_changed_at ON price_history(changed_at);
+CREATE INDEX IF NOT EXISTS idx_price_history_title ON price_history(product_title);
+CREATE INDEX IF NOT EXISTS idx_price_history_changed_at ON price_history(changed_at);
 
 CREATE OR REPLACE VIEW v_best_prices AS
 SELECT DISTINCT ON (title, category)
@@ -35,8 +78,9 @@ SELECT DISTINCT ON (title, category)
     raw_price,
     scraped_at
 FROM products
+WHERE condition = 'new' AND NOT is_bundle
 ORDER BY title, category, price ASC, scraped_at DESC;
 
 COMMENT ON TABLE products IS 'Produtos scrapeados da Pichau';
 COMMENT ON TABLE price_history IS 'Histórico de mudanças de preço';
-COMMENT ON VIEW v_best_prices IS 'Melhores preços por produto';
\ No newline at end of file
+COMMENT ON VIEW v_best_prices IS 'Melhores preços por produto (novos, sem kits)';
\ No newline at end of file